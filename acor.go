@@ -0,0 +1,214 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Defaults used by the ACO_R solver when its parameters are zero
+const (
+	defaultArchiveSize = 50
+	defaultACORAnts    = 2
+	defaultLocality    = 0.1
+	defaultSpeed       = 0.85
+)
+
+// A continuous optimization problem for ACO_R: minimize Objective over the box [lower, upper] of R^n
+type ACORProblem interface {
+	// The number of variables n
+	Dimensions() int
+	// The lower and upper bound of each variable
+	Bounds() (lower []float64, upper []float64)
+	// The value to minimize at x, which has Dimensions entries
+	Objective(x []float64) float64
+}
+
+// Parameters of the ACO_R solver. Zero values mean the defaults
+type ACORParams struct {
+	// Number of solutions kept in the archive, k; defaults to 50
+	ArchiveSize int
+	// Number of solutions sampled in every iteration, m; defaults to 2
+	Ants int
+	// Locality q: how strongly sampling favors the best solutions of the archive. Small values (e.g. 1e-4)
+	// intensify around the best solution, larger ones spread the sampling over the archive; defaults to 0.1
+	Locality float64
+	// Convergence speed xi: the width of the sampling kernels relative to the spread of the archive. Lower values
+	// converge faster; defaults to 0.85
+	Speed float64
+	// Seed for the random source; if omitted, runs aren't reproducible
+	Seed *int64
+}
+
+// A solution in the archive, with its objective value
+type archived struct {
+	x     []float64
+	value float64
+}
+
+// Solves continuous problems with ACO_R (Socha and Dorigo). Instead of a pheromone matrix over a graph, ACO_R keeps
+// an archive of the k best solutions found so far. Each new solution is sampled around an archived one, chosen
+// with a weight that decays with its rank (like the trails leading to a good tour), variable by variable from a
+// Gaussian whose width is the archive's spread in that variable. The new solutions replace the worst archived ones
+type ACORSolver struct {
+	problem ACORProblem
+	lower   []float64
+	upper   []float64
+	params  ACORParams
+	rng     *rand.Rand
+	archive []archived
+	// The ranks of the archive (0 for the best solution), and the probability of sampling around each of them
+	ranks      []uint
+	rankProbs  []float64
+	iterations int
+}
+
+// Create an ACO_R solver for a problem, with its archive filled with solutions drawn uniformly from the bounds.
+// Returns an error if the problem's bounds or the parameters are invalid
+func NewACORSolver(problem ACORProblem, params ACORParams) (*ACORSolver, error) {
+	n := problem.Dimensions()
+	lower, upper := problem.Bounds()
+
+	if n < 1 {
+		return nil, fmt.Errorf("%w: a continuous problem needs at least 1 dimension, got %d", ErrInvalidParams, n)
+	}
+
+	if len(lower) != n || len(upper) != n {
+		return nil, fmt.Errorf("%w: expected %d bounds, got %d lower and %d upper", ErrInvalidParams, n, len(lower), len(upper))
+	}
+
+	for i := 0; i < n; i++ {
+		if math.IsNaN(lower[i]) || math.IsInf(lower[i], 0) || math.IsNaN(upper[i]) || math.IsInf(upper[i], 0) || lower[i] > upper[i] {
+			return nil, fmt.Errorf("%w: bounds of variable %d must be finite with lower <= upper, got [%v, %v]", ErrInvalidParams, i, lower[i], upper[i])
+		}
+	}
+
+	if params.ArchiveSize == 0 {
+		params.ArchiveSize = defaultArchiveSize
+	}
+
+	if params.Ants == 0 {
+		params.Ants = defaultACORAnts
+	}
+
+	if params.Locality == 0 {
+		params.Locality = defaultLocality
+	}
+
+	if params.Speed == 0 {
+		params.Speed = defaultSpeed
+	}
+
+	// The spread of the archive is only defined with at least two solutions
+	if params.ArchiveSize < 2 || params.Ants < 1 {
+		return nil, fmt.Errorf("%w: need an archive of at least 2 solutions and at least 1 ant, got %d and %d", ErrInvalidParams, params.ArchiveSize, params.Ants)
+	}
+
+	if !(params.Locality > 0) || math.IsInf(params.Locality, 0) || !(params.Speed > 0) || math.IsInf(params.Speed, 0) {
+		return nil, fmt.Errorf("%w: locality and speed must be positive and finite, got %v and %v", ErrInvalidParams, params.Locality, params.Speed)
+	}
+
+	seed := time.Now().UnixNano()
+
+	if params.Seed != nil {
+		seed = *params.Seed
+	}
+
+	solver := &ACORSolver{
+		problem: problem,
+		lower:   append([]float64(nil), lower...),
+		upper:   append([]float64(nil), upper...),
+		params:  params,
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+
+	solver.rankProbs = rankProbabilities(params.ArchiveSize, params.Locality)
+
+	for l := 0; l < params.ArchiveSize; l++ {
+		solver.ranks = append(solver.ranks, uint(l))
+	}
+
+	for len(solver.archive) < params.ArchiveSize {
+		x := make([]float64, n)
+
+		for i := range x {
+			x[i] = lower[i] + solver.rng.Float64()*(upper[i]-lower[i])
+		}
+
+		solver.archive = append(solver.archive, archived{x: x, value: problem.Objective(x)})
+	}
+
+	solver.sortArchive()
+
+	return solver, nil
+}
+
+// The probability of choosing each rank l = 1, ..., k, proportional to the Gaussian weight
+// exp(-(l - 1)^2 / (2 q^2 k^2))
+func rankProbabilities(k int, q float64) []float64 {
+	weights := make([]float64, k)
+
+	for l := range weights {
+		weights[l] = math.Exp(-float64(l*l) / (2 * q * q * float64(k*k)))
+	}
+
+	return rouletteProbabilities(weights)
+}
+
+// Run num_iters iterations: in each, every ant samples a solution, and the archive keeps the k best solutions
+func (solver *ACORSolver) Run(num_iters int) {
+	for iter := 0; iter < num_iters; iter++ {
+		for ant := 0; ant < solver.params.Ants; ant++ {
+			x := solver.sample()
+			solver.archive = append(solver.archive, archived{x: x, value: solver.problem.Objective(x)})
+		}
+
+		solver.sortArchive()
+		solver.archive = solver.archive[:solver.params.ArchiveSize]
+		solver.iterations++
+	}
+}
+
+// Sample a solution around an archived one chosen by rank
+func (solver *ACORSolver) sample() []float64 {
+	k := solver.params.ArchiveSize
+	guide := solver.archive[weightedSampling(solver.rng, solver.ranks, solver.rankProbs)].x
+	x := make([]float64, len(guide))
+
+	for i := range x {
+		spread := 0.0
+
+		// Only the archive proper counts, not the solutions sampled earlier in this iteration
+		for _, solution := range solver.archive[:k] {
+			spread += math.Abs(solution.x[i] - guide[i])
+		}
+
+		sigma := solver.params.Speed * spread / float64(k-1)
+		x[i] = math.Min(math.Max(guide[i]+sigma*solver.rng.NormFloat64(), solver.lower[i]), solver.upper[i])
+	}
+
+	return x
+}
+
+// Sort the archive from the best solution to the worst. NaN objective values sort last
+func (solver *ACORSolver) sortArchive() {
+	sort.SliceStable(solver.archive, func(i, j int) bool {
+		a, b := solver.archive[i].value, solver.archive[j].value
+
+		return a < b || (!math.IsNaN(a) && math.IsNaN(b))
+	})
+}
+
+// The best solution found so far and its objective value. The solution is a copy
+func (solver *ACORSolver) Best() ([]float64, float64) {
+	best := solver.archive[0]
+
+	return append([]float64(nil), best.x...), best.value
+}
+
+// The number of iterations run so far
+func (solver *ACORSolver) IterationsRun() int {
+	return solver.iterations
+}
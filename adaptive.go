@@ -0,0 +1,111 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+)
+
+// The lambda used to measure diversity with the lambda-branching factor
+const adaptiveLambda = 0.05
+
+// A feedback controller that adjusts the evaporation rate (rho) and heuristic weight (beta) after every iteration,
+// based on the diversity of the pheromones as measured by the lambda-branching factor (with lambda = 0.05).
+// The control law is:
+//   - if the branching factor is below MinBranching, the colony is converging too fast: rho is multiplied by
+//     (1 + Step), so the trails are forgotten faster, and beta by (1 - Step), so the ants are less greedy
+//   - if the branching factor is above MaxBranching, the search is too diffuse: rho is multiplied by (1 - Step)
+//     and beta by (1 + Step), reinforcing the trails and the heuristic
+//   - otherwise, the parameters are left unchanged
+//
+// rho is always kept within [MinRho, MaxRho] and beta within [MinBeta, MaxBeta]
+type AdaptiveControl struct {
+	// The target range for the branching factor
+	MinBranching float64
+	MaxBranching float64
+	// The bounds for the evaporation rate, within (0, 1]
+	MinRho float64
+	MaxRho float64
+	// The bounds for the heuristic weight
+	MinBeta float64
+	MaxBeta float64
+	// The relative change applied to the parameters in every adjustment, within (0, 1)
+	Step float64
+}
+
+// The parameters chosen by the adaptive controller after an iteration
+type ParameterStep struct {
+	// The number of iterations run so far
+	Iteration int
+	// The branching factor the decision was based on
+	BranchingFactor float64
+	// The parameters used for the next iteration
+	Rho  float64
+	Beta float64
+}
+
+// The state of the adaptive controller
+type adaptiveState struct {
+	control AdaptiveControl
+	// The parameters before any adjustment, restored on Reset
+	initialRho  float64
+	initialBeta float64
+	trajectory  []ParameterStep
+}
+
+// Check that the controller's bounds make sense
+func (control AdaptiveControl) validate() error {
+	if control.MinBranching > control.MaxBranching {
+		return fmt.Errorf("%w: branching factor range [%v, %v] is empty", ErrInvalidParams, control.MinBranching, control.MaxBranching)
+	}
+
+	if !(control.MinRho > 0) || control.MinRho > control.MaxRho || control.MaxRho > 1 {
+		return fmt.Errorf("%w: rho bounds [%v, %v] must be within (0, 1]", ErrInvalidParams, control.MinRho, control.MaxRho)
+	}
+
+	if control.MinBeta < 0 || control.MinBeta > control.MaxBeta {
+		return fmt.Errorf("%w: beta bounds [%v, %v] must be a non-negative range", ErrInvalidParams, control.MinBeta, control.MaxBeta)
+	}
+
+	if !(control.Step > 0) || control.Step >= 1 {
+		return fmt.Errorf("%w: step must be within (0, 1), got %v", ErrInvalidParams, control.Step)
+	}
+
+	return nil
+}
+
+// Apply the control law after an iteration and record the chosen parameters
+func (colony *AntColony) adaptParameters() {
+	control := colony.adaptive.control
+	branching := colony.BranchingFactor(adaptiveLambda)
+
+	if branching < control.MinBranching {
+		colony.rho *= 1 + control.Step
+		colony.beta *= 1 - control.Step
+	} else if branching > control.MaxBranching {
+		colony.rho *= 1 - control.Step
+		colony.beta *= 1 + control.Step
+	}
+
+	colony.rho = math.Min(math.Max(colony.rho, control.MinRho), control.MaxRho)
+	colony.beta = math.Min(math.Max(colony.beta, control.MinBeta), control.MaxBeta)
+
+	colony.adaptive.trajectory = append(colony.adaptive.trajectory, ParameterStep{
+		Iteration:       colony.iterations,
+		BranchingFactor: branching,
+		Rho:             colony.rho,
+		Beta:            colony.beta,
+	})
+}
+
+// The parameters chosen by the adaptive controller after every iteration so far,
+// or nil if adaptive control isn't enabled
+func (colony *AntColony) ParameterTrajectory() []ParameterStep {
+	if colony.adaptive == nil {
+		return nil
+	}
+
+	trajectory := make([]ParameterStep, len(colony.adaptive.trajectory))
+	copy(trajectory, colony.adaptive.trajectory)
+
+	return trajectory
+}
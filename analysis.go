@@ -0,0 +1,119 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+)
+
+// The lambda-branching factor of the pheromone matrix, a standard convergence diagnostic.
+// For every node i, an outgoing edge (i, j) is counted as meaningful if
+// tau_ij >= tau_min + lambda * (tau_max - tau_min), where tau_min and tau_max are the smallest and largest
+// pheromone values on edges leaving i. The result is the average number of meaningful edges per node.
+// lambda is in [0, 1]: 0 counts every edge, while values close to 1 only count the dominant ones (0.05 is common).
+// A value close to 1 means that the ants almost always make the same choice, i.e. the colony has converged
+// (or 2 if both directions of each tour edge are reinforced)
+func (colony *AntColony) BranchingFactor(lambda float64) float64 {
+	if len(colony.constructionGraph.Nodes) == 0 {
+		return 0
+	}
+
+	total := 0
+
+	for i := range colony.constructionGraph.Nodes {
+		tauMin := math.Inf(1)
+		tauMax := math.Inf(-1)
+
+		for _, edge := range colony.constructionGraph.Edges[i] {
+			if edge.A == edge.B {
+				continue
+			}
+
+			tauMin = math.Min(tauMin, colony.Pheromones[edge.A][colony.column(edge)])
+			tauMax = math.Max(tauMax, colony.Pheromones[edge.A][colony.column(edge)])
+		}
+
+		threshold := tauMin + lambda*(tauMax-tauMin)
+
+		for _, edge := range colony.constructionGraph.Edges[i] {
+			if edge.A != edge.B && colony.Pheromones[edge.A][colony.column(edge)] >= threshold {
+				total++
+			}
+		}
+	}
+
+	return float64(total) / float64(len(colony.constructionGraph.Nodes))
+}
+
+// How confident the colony is about each step of a tour: for every edge (a, b) of the tour, the share of the
+// pheromone on (a, b) among all the edges that were feasible at a when the tour got there.
+// A value close to 1 means the colony almost always makes this move, while a small value marks a marginal decision.
+// The final edge, which closes the cycle, is the only feasible move and therefore always has confidence 1.
+// Edges that weren't feasible at that point (e.g. in an invalid tour) get confidence 0
+func (colony *AntColony) EdgeConfidence(tour []Edge) []float64 {
+	confidence := make([]float64, len(tour))
+
+	if len(tour) == 0 {
+		return confidence
+	}
+
+	initLocation := tour[0].A
+	visited := make(map[uint]bool)
+
+	for k, edge := range tour {
+		visited[edge.A] = true
+
+		if k == len(tour)-1 {
+			visited[initLocation] = false
+		}
+
+		denom := 0.0
+		feasible := false
+
+		for _, candidate := range colony.constructionGraph.Edges[edge.A] {
+			if visited[candidate.B] || candidate.A == candidate.B {
+				continue
+			}
+
+			denom += colony.Pheromones[candidate.A][colony.column(candidate)]
+
+			if candidate.B == edge.B {
+				feasible = true
+			}
+		}
+
+		if feasible && denom > 0 {
+			confidence[k] = colony.Pheromones[edge.A][colony.column(edge)] / denom
+		}
+	}
+
+	return confidence
+}
+
+// The distance between the pheromone matrices of two colonies built on the same graph, measured as the Frobenius
+// norm of their difference: sqrt(sum_ij (a_ij - b_ij)^2). This quantifies how differently two runs or strategies
+// ended up. Returns an error if the matrices don't have the same dimensions, or if only one of the colonies stores
+// them sparsely (see SparseProblem)
+func PheromoneDiff(a *AntColony, b *AntColony) (float64, error) {
+	if (a.columns == nil) != (b.columns == nil) {
+		return 0, fmt.Errorf("%w: only one of the pheromone matrices is sparse", ErrInvalidParams)
+	}
+
+	if len(a.Pheromones) != len(b.Pheromones) {
+		return 0, fmt.Errorf("%w: pheromone matrices have %d and %d rows", ErrRaggedMatrix, len(a.Pheromones), len(b.Pheromones))
+	}
+
+	sum := 0.0
+
+	for i := range a.Pheromones {
+		if len(a.Pheromones[i]) != len(b.Pheromones[i]) {
+			return 0, fmt.Errorf("%w: pheromone matrix rows %d have %d and %d entries", ErrRaggedMatrix, i, len(a.Pheromones[i]), len(b.Pheromones[i]))
+		}
+
+		for j := range a.Pheromones[i] {
+			diff := a.Pheromones[i][j] - b.Pheromones[i][j]
+			sum += diff * diff
+		}
+	}
+
+	return math.Sqrt(sum), nil
+}
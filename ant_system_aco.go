@@ -1,19 +1,23 @@
 package antcolony
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"sort"
+	"sync"
+	"time"
 )
 
-// Exp. decay rate for the pheromone
-const rho = 0.5
+// Default exp. decay rate for the pheromone: the fraction of the pheromone that evaporates every iteration
+const defaultRho = 0.5
 
-// Pheromone weight
-const alpha = 1.0
+// Default pheromone weight
+const defaultAlpha = 1.0
 
-// Heuristic weight
-const beta = 3.0
+// Default heuristic weight
+const defaultBeta = 3.0
 
 // Ant-Cycle Implementation
 
@@ -26,181 +30,1581 @@ type ACOptimizable interface {
 	// the length of a cycle constructed with a nearest neighbour (greedy) heuristic
 	InitPheromones(num_ants uint) [][]float64
 	// Similarily, how should the heuristics be initialized?
+	// Heuristics are directional: heuristics[a][b] is the attractiveness of moving from a to b,
+	// and need not equal heuristics[b][a] (e.g. in the asymmetric TSP)
 	InitHeuristics() [][]float64
 }
 
+// A problem with constraints that depend on the ant's partial solution, e.g. time windows that depend on the
+// elapsed time. If a problem implements this, ants only take edges it deems feasible, and an ant that is left
+// without feasible edges is stuck: its incomplete tour is neither recorded nor deposited. Deposits only reinforce
+// the edges of a tour that are feasible where the tour takes them, so tours that didn't come from the ants (an
+// initial tour, migrants, or tours passed to DepositTour) can't steer the ants towards edges they'd be refused
+type ConstrainedProblem interface {
+	ACOptimizable
+	// Can an ant that has constructed tour so far take edge next? edge.A is the ant's current component
+	// (the tour is empty for the first move). tour must not be modified
+	Feasible(tour []Edge, edge Edge) bool
+}
+
 type AntColony struct {
+	// The problem the colony optimizes
+	problem ACOptimizable
 	// The construction graph G = (C, L) of the problem
 	// C is the set of components (e.g. cities in TSP or items in KS)
 	// and L is the set of connections (in TSP, for example, all pairs of cities are connected)
 	constructionGraph Graph
-	// Pheromones on connections - this is increased every time an ant steps on the edge
+	// Pheromones on connections - this is increased every time an ant steps on the edge. The entry for edge (a, b)
+	// is Pheromones[a][b], unless the problem is a SparseProblem: then row a has an entry per edge in
+	// Graph.Edges[a], in the same order
 	Pheromones [][]float64
 	// We can also have heuristic information on the arcs - for TSP, this is the repriocorial of the cost of the edge
 	heuristics [][]float64
+	// For a SparseProblem, the position of each edge's entry in its row of the matrices; nil for the dense layout
+	columns []map[uint]int
 	// The ants
 	ants     []Ant
 	num_ants uint
+	// The best tour found so far across all ants and iterations, and its cost
+	bestTour []Edge
+	bestCost float64
+	// The worst tour completed so far, and its cost
+	worstTour []Edge
+	worstCost float64
+	// The pheromone weight, heuristic weight and evaporation rate used by this colony
+	alpha float64
+	beta  float64
+	rho   float64
+	// Whether beta decays as the ants' tours grow, and the fraction of it left at the end of a tour
+	progressiveBeta bool
+	betaFloor       float64
+	// Whether edges costing more than the threshold (the given percentile of the edge costs) have their scores
+	// multiplied by the factor during construction
+	longEdgePenalty    bool
+	longEdgePercentile float64
+	longEdgeThreshold  float64
+	longEdgeFactor     float64
+	// How many sources to sample for the betweenness-scaled initial pheromones (0 if they aren't scaled)
+	betweennessSamples int
+	// How many tours each ant constructs per iteration, and whether only the best of them is deposited
+	toursPerAnt     int
+	depositBestTour bool
+	// A soft cap on the time the ants spend constructing their tours in an iteration (0 if there is none)
+	iterationTimeout time.Duration
+	// The context of the current run, if it's cancellable
+	ctx context.Context
+	// How many nearest neighbours each component's candidate lists hold (0 if candidate lists aren't used), the
+	// out- and in-candidates of each component, and whether each edge leads to an out-candidate
+	candidateCount int
+	outCandidates  [][]uint
+	inCandidates   [][]uint
+	isCandidate    [][]bool
+	// How many iterations in a row the best-so-far hasn't improved, after how many such iterations the search
+	// restarts (0 if never), whether the best-so-far survives a restart, and how many restarts there were
+	stagnation      int
+	restartAfter    int
+	restartKeepBest bool
+	restarts        int
+	// How much of the mean initial pheromone is added to the best tour's edges on a restart
+	restartBias float64
+	// How many iterations in a row the best tour of the whole run hasn't improved (unlike stagnation, restarts
+	// don't reset this), and after how many such iterations runs stop early (0 if never)
+	unimproved int
+	stopAfter  int
+	// The best tour from before the restarts that forgot it, with the iteration and time it was found at
+	overallTour      []Edge
+	overallCost      float64
+	overallIteration int
+	overallTime      time.Duration
+	// The most a single deposit can add to an edge (0 if unbounded)
+	maxSingleDeposit float64
+	// The summed top-candidate probabilities of the construction steps in the current iteration, and how many
+	// steps were summed
+	exploitationSum   float64
+	exploitationSteps int
+	// The tolerance within which costs are considered equal, relative to their magnitude
+	epsilon float64
+	// The probability of choosing the best candidate outright (ACS's q0), the local pheromone decay applied to every
+	// edge an ant takes (0 if none), and whether the ants are constructing the tours of an iteration
+	q0           float64
+	localDecay   float64
+	constructing bool
+	// Whether the pheromones are kept in [0, 1] by the hyper-cube framework, and the sum of 1 / C over the
+	// iteration's depositing tours that normalizes the deposits
+	hyperCube   bool
+	depositNorm float64
+	// How many goroutines construct the ants' tours (1 for sequential construction)
+	workers int
+	// The custom construction used instead of DoCycle (nil for the built-in one), and the last error it returned
+	constructor     Constructor
+	constructionErr error
+	constructionMu  sync.Mutex
+	// Every how many iterations the best-so-far tour is recorded as a frame (0 if never), and the frames
+	frameInterval int
+	frames        []TourFrame
+	// A tour deposited on before the first iteration, and a tour the ants' tours must beat to become the
+	// best-so-far, which is reported by BestSolution until one does
+	initialTour  []Edge
+	baselineTour []Edge
+	baselineCost float64
+	// The mean tour cost and branching factor in every iteration, and the callback reporting each iteration's
+	// statistics
+	meanCosts        []float64
+	branchingFactors []float64
+	onIteration      func(stats IterationStats)
+	// Observers called at the end of every iteration, after onIteration
+	observers []IterationObserver
+	// How strongly each ant is biased towards the edges of its own last tour; 0 disables the memory
+	memoryWeight float64
+	// The probability of each component being an ant's start, if the starts aren't uniform
+	startDistribution []float64
+	// Whether the scores are precomputed in every iteration, the table holding them, and whether it's
+	// currently up to date
+	batchedScoring bool
+	scoreTable     [][]float64
+	scoresBatched  bool
+	// The problem's partial-solution constraints, if it has any
+	constraints ConstrainedProblem
+	// The problem's edge costs, if it provides them
+	costs CostProblem
+	// The local search applied to the ants' tours before they're recorded, if any
+	localSearch LocalSearch
+	// How to compute the cost of a tour; if nil, the cost is the sum of the edge costs
+	costFunc TourCost
+	// The elitist weight as a function of the progress through the current run, if set
+	elitistSchedule func(iter, total int) float64
+	// The iteration at which the current call to RunSimulation started, and how many iterations it will run
+	runStart  int
+	runLength int
+	// How many iterations have been run, and how many tours have been constructed in them
+	iterations       int
+	toursConstructed int
+	// If positive, RunSimulation runs until this many tours have been constructed instead of a fixed number of iterations
+	tourBudget int
+	// The pheromones the colony started with, restored on Reset
+	initialPheromones [][]float64
+	// How many times each edge has appeared in a tour that deposited pheromones
+	edgeUsage [][]uint
+	// How ants choose the next component among the feasible candidates
+	selection      SelectionMethod
+	tournamentSize int
+	// How the pheromones are updated at the end of each iteration
+	strategy PheromoneStrategy
+	// If set, the number of ants used in each iteration, given the number of iterations run so far
+	antSchedule func(iter int) int
+	// Whether construction ignores the pheromones altogether
+	pheromoneDisabled bool
+	// How many times ants may visit specific components, e.g. depots; other components are visited once
+	capacities map[uint]int
+	// The probability that an ant reads the actual pheromone on a candidate edge rather than the initial one
+	perception float64
+	// If positive, the pheromones on the edges leaving each node are rescaled to sum to this after every update
+	rowTarget float64
+	// Whether candidate scores are computed in log-space
+	logSpace bool
+	// The iterations after which onCheckpoint is called with the best tour so far
+	checkpoints  map[int]bool
+	onCheckpoint func(iter int, tour []Edge, cost float64)
+	// The colony whose pheromones the initial pheromones are transferred from, if any
+	transferSource *AntColony
+	// If set, rho and beta are adjusted after every iteration based on the diversity of the pheromones
+	adaptive *adaptiveState
+	// Whether the ants start with greedy tours instead of constructing their first ones
+	greedySeeds bool
+	// A sequence of components that every ant must visit, in order, before constructing the rest of its tour
+	fixedPrefix []uint
+	// How ties with the best-so-far are handled, the distinct equal-cost optima kept with TieKeepAll,
+	// and the last few tours that became the best-so-far
+	tieBreak    TieBreakPolicy
+	optima      [][]Edge
+	recentBests [][]Edge
+	// The random source used by the colony; all randomness should go through it
+	rng  *rand.Rand
+	seed int64
+	// The source set with WithRandSource, used instead of one seeded with seed
+	source rand.Source
+	// If set, every random draw is recorded, or the draws are taken from a recorded stream
+	recorder *RNGRecorder
+	replay   []int64
+	// The ordered sequence of moves the ant that found the best tour made, captured as it was built
+	bestConstruction []Edge
+	// When the colony was created (or reset), and how many iterations had been completed and how much time had
+	// passed since then when the best tour was found
+	created       time.Time
+	bestIteration int
+	timeToBest    time.Duration
 }
 
 // An individual ant
 type Ant struct {
 	// The index of the current component, i.e. the current vertex in the construction graph
 	currComponent uint
-	// Which components has this ant already visited?
+	// How many times has this ant visited each component?
 	// Used to define constraints
-	memory map[uint]bool
+	memory map[uint]int
+	// How many distinct components has this ant visited?
+	numVisited int
+	// Where the current tour started; the tour ends by returning here
+	start uint
 	// We also store the explicit edges to compute the pheromones
 	tour []Edge
+	// The edges of the ant's last complete tour, which survive ResetSolution. Only kept with WithAntMemory
+	lastTour map[Edge]bool
+	// The ant's own random source while it constructs in parallel with other ants (nil otherwise)
+	rng *rand.Rand
+	// The summed top-candidate probabilities of the choices the ant made since they were last collected, and how
+	// many choices were summed
+	topMass float64
+	choices int
+}
+
+// The random source the ant draws from during construction
+func (ant *Ant) random(colony *AntColony) *rand.Rand {
+	if ant.rng != nil {
+		return ant.rng
+	}
+
+	return colony.rng
 }
 
-// Construct a new ant colony for an ACOptimizable problem with num_ants ants
-func NewAntColony(problem ACOptimizable, num_ants uint) *AntColony {
+// Construct a new ant colony for an ACOptimizable problem, configured with opts. Unless set otherwise with WithAnts,
+// the colony has one ant per component.
+// Returns an error if the problem is malformed or any of the options is invalid for it; check for the
+// kind of error with errors.Is
+func NewAntColony(problem ACOptimizable, opts ...Option) (*AntColony, error) {
 	colony := new(AntColony)
+	colony.problem = problem
 	colony.constructionGraph = problem.ConstructGraph()
-	colony.Pheromones = problem.InitPheromones(num_ants)
-	colony.heuristics = problem.InitHeuristics()
-	colony.num_ants = num_ants
+
+	if err := colony.constructionGraph.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(colony.constructionGraph.Nodes) == 0 {
+		return nil, fmt.Errorf("%w: graph has no nodes", ErrInvalidGraph)
+	}
+
+	if !colony.constructionGraph.stronglyConnected() {
+		return nil, fmt.Errorf("%w: not every component can reach every other one", ErrDisconnectedGraph)
+	}
+
+	sparse, isSparse := problem.(SparseProblem)
+
+	if isSparse {
+		columns, err := sparseColumns(colony.constructionGraph)
+
+		if err != nil {
+			return nil, err
+		}
+
+		colony.columns = columns
+	}
+
+	// Problems with several heuristics have them blended into one
+	if multi, ok := problem.(MultiHeuristicProblem); ok {
+		heuristics, weights := multi.InitHeuristicSet()
+		combined, err := CombineHeuristics(heuristics, weights)
+
+		if err != nil {
+			return nil, err
+		}
+
+		colony.heuristics = combined
+	} else if isSparse {
+		colony.heuristics = sparse.InitSparseHeuristics()
+	} else {
+		colony.heuristics = problem.InitHeuristics()
+	}
+
+	if err := colony.validateHeuristics(colony.heuristics); err != nil {
+		return nil, err
+	}
+
+	colony.zeroDiagonal(colony.heuristics)
+
+	if constrained, ok := problem.(ConstrainedProblem); ok {
+		colony.constraints = constrained
+	}
+
+	if costs, ok := problem.(CostProblem); ok {
+		colony.costs = costs
+	}
+
+	colony.num_ants = uint(len(colony.constructionGraph.Nodes))
 	colony.ants = make([]Ant, 0)
+	colony.bestCost = math.Inf(1)
+	colony.worstCost = math.Inf(-1)
+	colony.edgeUsage = colony.newUsageMatrix()
+	colony.tournamentSize = defaultTournamentSize
+	colony.epsilon = defaultEpsilon
+	colony.alpha = defaultAlpha
+	colony.beta = defaultBeta
+	colony.rho = defaultRho
+	colony.perception = 1
+	colony.strategy = AntCycleStrategy{}
+	colony.toursPerAnt = 1
+	colony.workers = 1
+	colony.seed = time.Now().UnixNano()
+	colony.restartKeepBest = true
+	colony.overallCost = math.Inf(1)
+	colony.created = time.Now()
+
+	for _, opt := range opts {
+		if err := opt(colony); err != nil {
+			return nil, err
+		}
+	}
+
+	// The initial pheromones may depend on the number of ants, which is only known once the options are applied
+	if isSparse {
+		colony.Pheromones = sparse.InitSparsePheromones(colony.num_ants)
+	} else {
+		colony.Pheromones = problem.InitPheromones(colony.num_ants)
+	}
+
+	// If the initial pheromones vanish, every score is zero and the first iteration degenerates
+	if err := colony.validatePheromones(colony.Pheromones); err != nil {
+		return nil, err
+	}
+
+	if colony.transferSource != nil {
+		colony.applyTransfer()
+	}
+
+	colony.initialPheromones = copyMatrix(colony.Pheromones)
+
+	if colony.elitistSchedule != nil {
+		if _, ok := colony.strategy.(ElitistStrategy); !ok {
+			return nil, fmt.Errorf("%w: an elitist schedule requires the ElitistStrategy", ErrInvalidParams)
+		}
+	}
+
+	if colony.startDistribution != nil && len(colony.fixedPrefix) > 0 {
+		return nil, fmt.Errorf("%w: a start distribution can't be combined with a fixed prefix", ErrInvalidParams)
+	}
+
+	// With these the scores change during construction, so they can't be precomputed
+	if colony.batchedScoring && (colony.perception < 1 || colony.progressiveBeta || colony.localDecay > 0) {
+		return nil, fmt.Errorf("%w: batched scoring can't be combined with perception noise, progressive beta or a local pheromone update", ErrInvalidParams)
+	}
+
+	// Concurrent ants can't share the random source for the perception noise, or update the pheromones as they go
+	if colony.workers > 1 && (colony.perception < 1 || colony.localDecay > 0) {
+		return nil, fmt.Errorf("%w: parallel construction can't be combined with perception noise or a local pheromone update", ErrInvalidParams)
+	}
+
+	// The random source depends on several options, so we only create it once they have all been applied
+	colony.rng = rand.New(colony.randSource())
+
+	if colony.betweennessSamples > 0 {
+		colony.applyBetweennessInit()
+	}
+
+	if colony.hyperCube {
+		colony.scaleToHyperCube()
+	}
+
+	// The adaptive controller starts from the parameters as configured by all the options
+	if colony.adaptive != nil {
+		colony.adaptive.initialRho = colony.rho
+		colony.adaptive.initialBeta = colony.beta
+	}
 
 	// Initialize all the ants
-	for i := 0; i < int(num_ants); i++ {
-		// Generate a random city
-		rand_component := rand.Intn(len(colony.constructionGraph.Nodes))
-		// Append the ant to the ant list
-		ant_memory := make(map[uint]bool)
-		//ant_memory[uint(rand_component)] = true
-		colony.ants = append(colony.ants, Ant{uint(rand_component), ant_memory, make([]Edge, 0)})
+	for i := 0; i < int(colony.num_ants); i++ {
+		colony.ants = append(colony.ants, colony.newAnt())
 	}
 
-	return colony
+	if colony.greedySeeds {
+		colony.seedGreedyTours()
+	}
+
+	colony.applyWarmStart()
+
+	return colony, nil
 }
 
+// Construct a fresh ant located at a random component
+func (colony *AntColony) newAnt() Ant {
+	ant_memory := make(map[uint]int)
+
+	return Ant{currComponent: colony.startComponent(colony.rng), memory: ant_memory, tour: make([]Edge, 0)}
+}
+
+// Give each ant a greedy tour from a distinct start component (cycling through the components if there
+// are more ants than components). The ants already have complete tours, so in the first iteration they
+// don't construct anything and deposit on their greedy tours instead. The seeds are also recorded as the
+// initial best-so-far, so the reported best is never worse than the best greedy tour
+func (colony *AntColony) seedGreedyTours() {
+	n := len(colony.constructionGraph.Nodes)
+
+	for i := range colony.ants {
+		ant := &colony.ants[i]
+
+		// With a fixed prefix all the ants must start at its beginning
+		if len(colony.fixedPrefix) == 0 {
+			ant.currComponent = uint(i % n)
+		}
+
+		ant.greedyCycle(colony)
+		colony.recordTour(ant)
+	}
+}
+
+// Where should an ant start its tour? If a prefix is fixed, every ant starts at its first component.
+// Otherwise we draw a random component from rng, following the start distribution if one was set.
+// When the ants construct in parallel, rng must be the ant's own random source.
+// rand.Intn takes an int, which can hold any node count, since the components index into slices
+func (colony *AntColony) startComponent(rng *rand.Rand) uint {
+	if len(colony.fixedPrefix) > 0 {
+		return colony.fixedPrefix[0]
+	}
+
+	if colony.startDistribution != nil {
+		return weightedSampling(rng, colony.constructionGraph.Nodes, colony.startDistribution)
+	}
+
+	return uint(rng.Intn(len(colony.constructionGraph.Nodes)))
+}
+
+// Run the simulation for num_iters iterations. If a total tour budget was set with WithTotalTourBudget,
+// iterations are instead run until that many tours have been constructed in this call, regardless of num_iters.
+// Either way, the run stops early once the colony has converged as set with WithEarlyStopping
 func (colony *AntColony) RunSimulation(num_iters int) {
-	for i := 0; i < num_iters; i++ {
-		// Have each ant complete a cycle
-		for i := 0; i < int(colony.num_ants); i++ {
-			colony.ants[i].DoCycle(colony)
+	colony.RunSimulationContext(context.Background(), num_iters)
+}
+
+// Run the simulation like RunSimulation until it's done or ctx is cancelled, e.g. to abort a long run from a server
+// handler. Cancellation is checked between iterations and between ants: the ants that haven't started their tours
+// when it happens don't construct them, and the iteration finishes with the tours constructed so far (at least one).
+// Returns the best tour found so far and its cost, and ctx.Err() if the run was cancelled
+func (colony *AntColony) RunSimulationContext(ctx context.Context, num_iters int) ([]Edge, float64, error) {
+	colony.ctx = ctx
+	defer func() { colony.ctx = nil }()
+
+	startTours := colony.toursConstructed
+	colony.runStart = colony.iterations
+	colony.runLength = num_iters
+
+	// With a budget the length of the run is only known approximately, since the ant count may change
+	if colony.tourBudget > 0 && colony.num_ants > 0 {
+		perIteration := int(colony.num_ants) * colony.toursPerAnt
+		colony.runLength = (colony.tourBudget + perIteration - 1) / perIteration
+	}
+
+	for i := 0; ; i++ {
+		if ctx.Err() != nil || colony.Converged() {
+			break
+		}
+
+		if colony.tourBudget > 0 {
+			if colony.toursConstructed-startTours >= colony.tourBudget {
+				break
+			}
+		} else if i >= num_iters {
+			break
+		}
+
+		colony.runIteration()
+	}
+
+	tour, cost := colony.BestSolution()
+
+	return tour, cost, ctx.Err()
+}
+
+// Run a single iteration: every ant constructs a tour, then the pheromones are updated
+func (colony *AntColony) runIteration() {
+	if colony.antSchedule != nil {
+		colony.resizeAnts(colony.antSchedule(colony.iterations))
+	}
+
+	if colony.batchedScoring {
+		colony.buildScoreTable()
+	}
+
+	previousBest := colony.bestCost
+	_, previousOverall := colony.overallBest()
+	costs := newIterationCosts()
+	// Have each ant complete its cycles
+	depositing, constructed := colony.constructTours(&costs)
+
+	// The pheromones are about to change, so the table is stale
+	colony.scoresBatched = false
+
+	colony.toursConstructed += constructed
+
+	if colony.hyperCube {
+		colony.depositNorm = colony.hyperCubeNorm(depositing)
+	}
+
+	// Evaporate the pheromones to avoid converging on a suboptimal solution
+	colony.strategy.Evaporate(colony)
+	// Update the pheromones from all the ants
+	colony.strategy.Deposit(colony, depositing)
+	colony.postUpdate()
+
+	// We index into the slice since ranging over it would reset a copy of each ant
+	for i := range colony.ants {
+		// We want a clean slate for our ant in the next iteration
+		colony.ants[i].ResetSolution(colony)
+	}
+
+	colony.iterations++
+
+	if colony.adaptive != nil {
+		colony.adaptParameters()
+	}
+
+	if colony.checkpoints[colony.iterations] && colony.onCheckpoint != nil {
+		tour, cost := colony.BestSolution()
+		colony.onCheckpoint(colony.iterations, tour, cost)
+	}
+
+	colony.recordFrame()
+	colony.recordStats(costs)
+	colony.checkStagnation(previousBest)
+	colony.checkImprovement(previousOverall)
+}
+
+// Have every ant construct its tours for the iteration, recording them and adding their costs to costs. Returns
+// the ants whose tours should be deposited and the number of tours constructed. With a single tour per ant, these
+// are the ants themselves; otherwise they're snapshots of the ants taken after each tour (or after each ant's best
+// tour), since the ants are reset to construct their next tour from a new random start. Once the iteration
+// timeout has passed, the remaining tours aren't constructed (the first one always is, so the run progresses)
+func (colony *AntColony) constructTours(costs *iterationCosts) ([]Ant, int) {
+	start := time.Now()
+	constructed := 0
+	colony.constructing = true
+	defer func() { colony.constructing = false }()
+	colony.exploitationSum = 0
+	colony.exploitationSteps = 0
+	snapshots := make([]Ant, 0, len(colony.ants)*colony.toursPerAnt)
+	bestAnts := make([]Ant, len(colony.ants))
+	bestCosts := make([]float64, len(colony.ants))
+
+	for i := range bestCosts {
+		bestCosts[i] = math.Inf(1)
+	}
+
+	// Record the tour an ant just constructed
+	account := func(i int) {
+		ant := &colony.ants[i]
+		constructed++
+		colony.exploitationSum += ant.topMass
+		colony.exploitationSteps += ant.choices
+		ant.topMass = 0
+		ant.choices = 0
+		colony.improveTour(ant)
+		cost, ok := colony.recordTour(ant)
+
+		if ok {
+			costs.add(cost)
+
+			for _, edge := range ant.tour {
+				colony.edgeUsage[edge.A][colony.column(edge)]++
+			}
+		}
+
+		if colony.toursPerAnt == 1 {
+			return
 		}
 
-		// Evaporate the pheromones to avoid converging on a suboptimal solution
-		colony.EvaporatePheromones()
-		// Update the pheromones from all the ants
-		for _, ant := range colony.ants {
-			ant.DepositPheromones(colony)
-			// We want a clean slate for our ant in the next iteration
-			ant.ResetSolution(colony)
+		// The snapshot keeps the tour, since resetting the ant gives it a new one
+		if !colony.depositBestTour {
+			snapshots = append(snapshots, *ant)
+		} else if ok && cost < bestCosts[i] {
+			bestAnts[i] = *ant
+			bestCosts[i] = cost
 		}
 	}
+
+	for round := 0; round < colony.toursPerAnt; round++ {
+		if colony.workers > 1 {
+			built := colony.constructConcurrently(round, start)
+			complete := true
+
+			// The tours are recorded in the ants' order, so the results don't depend on the scheduling
+			for i := range colony.ants {
+				if built[i] {
+					account(i)
+				} else {
+					complete = false
+				}
+			}
+
+			if !complete {
+				return colony.depositedAnts(snapshots, bestAnts, bestCosts), constructed
+			}
+
+			continue
+		}
+
+		for i := range colony.ants {
+			if constructed > 0 && colony.stopConstructing(start) {
+				return colony.depositedAnts(snapshots, bestAnts, bestCosts), constructed
+			}
+
+			if round > 0 {
+				colony.ants[i].ResetSolution(colony)
+			}
+
+			colony.construct(&colony.ants[i])
+			account(i)
+		}
+	}
+
+	return colony.depositedAnts(snapshots, bestAnts, bestCosts), constructed
+}
+
+// Should the ants stop constructing tours for this iteration, because the iteration timeout has passed or the run
+// was cancelled?
+func (colony *AntColony) stopConstructing(start time.Time) bool {
+	if colony.iterationTimeout > 0 && time.Since(start) > colony.iterationTimeout {
+		return true
+	}
+
+	return colony.ctx != nil && colony.ctx.Err() != nil
+}
+
+// Replace the ant's complete tour with the one the local search makes of it. The result is only taken if it's a
+// complete tour over the edges of the graph (and feasible, for a ConstrainedProblem) that costs no more than the
+// original, so a search that doesn't fit the problem can't corrupt the ant's tour
+func (colony *AntColony) improveTour(ant *Ant) {
+	if colony.localSearch == nil || !colony.IsComplete(ant) {
+		return
+	}
+
+	improved := colony.localSearch.Improve(ant.tour, colony.problem)
+
+	if ValidateTour(improved, len(colony.constructionGraph.Nodes)) != nil {
+		return
+	}
+
+	for i, edge := range improved {
+		if !colony.constructionGraph.hasEdge(edge.A, edge.B) {
+			return
+		}
+
+		if colony.constraints != nil && !colony.constraints.Feasible(improved[:i:i], edge) {
+			return
+		}
+	}
+
+	if colony.tourCost(improved) <= colony.tourCost(ant.tour) {
+		ant.tour = improved
+	}
+}
+
+// The ants whose tours should be deposited, given the snapshots taken by constructTours
+func (colony *AntColony) depositedAnts(snapshots []Ant, bestAnts []Ant, bestCosts []float64) []Ant {
+	// The ants that weren't reached before a timeout have empty tours, so they don't deposit anything
+	if colony.toursPerAnt == 1 {
+		return colony.ants
+	}
+
+	if colony.depositBestTour {
+		for i := range bestAnts {
+			if !math.IsInf(bestCosts[i], 1) {
+				snapshots = append(snapshots, bestAnts[i])
+			}
+		}
+	}
+
+	return snapshots
+}
+
+// Restore the colony to its initial state: the pheromones are reset to their initial values, and the best tour,
+// the edge usage statistics, the cost history, the frames and the iteration counters are cleared. The configuration
+// and random source are kept, and the greedy seeds and an initial tour are applied again
+func (colony *AntColony) Reset() {
+	colony.Pheromones = copyMatrix(colony.initialPheromones)
+	colony.bestTour = nil
+	colony.bestCost = math.Inf(1)
+	colony.bestConstruction = nil
+	colony.worstTour = nil
+	colony.worstCost = math.Inf(-1)
+	colony.optima = nil
+	colony.recentBests = nil
+	colony.edgeUsage = colony.newUsageMatrix()
+	colony.iterations = 0
+	colony.toursConstructed = 0
+	colony.meanCosts = nil
+	colony.branchingFactors = nil
+	colony.frames = nil
+	colony.stagnation = 0
+	colony.restarts = 0
+	colony.unimproved = 0
+	colony.overallTour = nil
+	colony.overallCost = math.Inf(1)
+	colony.constructionErr = nil
+	colony.created = time.Now()
+
+	if colony.adaptive != nil {
+		colony.rho = colony.adaptive.initialRho
+		colony.beta = colony.adaptive.initialBeta
+		colony.adaptive.trajectory = nil
+	}
+
+	for i := range colony.ants {
+		colony.ants[i].ResetSolution(colony)
+		colony.ants[i].lastTour = nil
+	}
+
+	if colony.greedySeeds {
+		colony.seedGreedyTours()
+	}
+
+	colony.applyWarmStart()
 }
 
-func (colony *AntColony) GetSolution() []Edge {
-	colony.ants[0].DoCycle(colony)
+// How many times each edge appeared in a complete tour constructed by an ant in the iterations of the whole run.
+// Only the ants' own tours count: the extra deposits of the pheromone strategies (elitist, best-so-far, restart
+// bias, initial tours, migrants) reinforce edges without an ant taking them. Unlike the pheromones, which also
+// decay, this shows which connections the colony consistently favors. The counts have the same layout as Pheromones
+func (colony *AntColony) EdgeUsage() [][]uint {
+	usage := make([][]uint, len(colony.edgeUsage))
+
+	for i := range colony.edgeUsage {
+		usage[i] = make([]uint, len(colony.edgeUsage[i]))
+		copy(usage[i], colony.edgeUsage[i])
+	}
+
+	return usage
+}
 
-	return colony.ants[0].tour
+// Grow or shrink the ant pool to num_ants ants. Existing ants are kept as they are (they have a clean slate
+// between iterations anyway), new ants are initialized like the original ones, drawing their start from the colony RNG
+func (colony *AntColony) resizeAnts(num_ants int) {
+	// An iteration without ants would construct nothing, so the pool never empties
+	if num_ants < 1 {
+		num_ants = 1
+	}
+
+	for len(colony.ants) < num_ants {
+		colony.ants = append(colony.ants, colony.newAnt())
+	}
+
+	colony.ants = colony.ants[:num_ants]
+	colony.num_ants = uint(num_ants)
+}
+
+// Passes over the pheromones applied after every update, once the strategy has evaporated and deposited
+func (colony *AntColony) postUpdate() {
+	if colony.rowTarget > 0 {
+		colony.normalizeRows()
+	}
+}
+
+// Rescale the pheromones on the edges leaving each node so they sum to the row target
+func (colony *AntColony) normalizeRows() {
+	for i := range colony.constructionGraph.Edges {
+		sum := 0.0
+
+		for _, edge := range colony.constructionGraph.Edges[i] {
+			if edge.A != edge.B {
+				sum += colony.Pheromones[edge.A][colony.column(edge)]
+			}
+		}
+
+		if sum <= 0 {
+			continue
+		}
+
+		for _, edge := range colony.constructionGraph.Edges[i] {
+			if edge.A != edge.B {
+				colony.Pheromones[edge.A][colony.column(edge)] *= colony.rowTarget / sum
+			}
+		}
+	}
+}
+
+// The total number of iterations run by the colony so far. With WithTotalTourBudget, this
+// tells how many iterations the budget translated to
+func (colony *AntColony) IterationsRun() int {
+	return colony.iterations
+}
+
+// The total number of tours constructed by the colony's ants so far
+func (colony *AntColony) ToursConstructed() int {
+	return colony.toursConstructed
+}
+
+// Returns a copy of the construction graph the colony was created with. The colony never changes its graph,
+// and mutating the returned copy doesn't affect the colony
+func (colony *AntColony) Graph() Graph {
+	return colony.constructionGraph.clone()
+}
+
+// The number of components in the construction graph
+func (colony *AntColony) NumNodes() int {
+	return len(colony.constructionGraph.Nodes)
+}
+
+// Constructs one more tour with the current pheromones and returns it with its cost, as computed by the problem's
+// cost function (+Inf if the ant got stuck). The tour is sampled, so it's often worse than the best tour seen during
+// the run; use BestSolution for that. Like Sample, this uses a scratch ant, so the colony's ants are left as they are
+func (colony *AntColony) GetSolution() TourResult {
+	ant := colony.newAnt()
+	colony.construct(&ant)
+
+	if !colony.IsComplete(&ant) {
+		return TourResult{Tour: ant.tour, Cost: math.Inf(1)}
+	}
+
+	return TourResult{Tour: ant.tour, Cost: colony.tourCost(ant.tour)}
+}
+
+// Returns the best tour found so far and its cost. The cost is +Inf if no tour has been completed yet.
+// This is the best tour over the whole run, even if a restart made the search forget it, or the baseline set with
+// WithBaselineBest if no tour has beaten it
+func (colony *AntColony) BestSolution() ([]Edge, float64) {
+	best, cost := colony.overallBest()
+
+	if colony.baselineTour != nil && !colony.improves(cost, colony.baselineCost) {
+		best, cost = colony.baselineTour, colony.baselineCost
+	}
+
+	tour := make([]Edge, len(best))
+	copy(tour, best)
+
+	return tour, cost
+}
+
+// Returns the worst tour completed so far and its cost, which together with the best tour shows the spread of
+// the solutions found. The cost is -Inf if no tour has been completed yet
+func (colony *AntColony) WorstTour() ([]Edge, float64) {
+	tour := make([]Edge, len(colony.worstTour))
+	copy(tour, colony.worstTour)
+
+	return tour, colony.worstCost
+}
+
+// Returns the exact sequence of moves made by the ant that found the best tour, in the order
+// they were made. This shows how the best solution was assembled, not just which edges it contains
+func (colony *AntColony) BestTourConstruction() []Edge {
+	construction := make([]Edge, len(colony.bestConstruction))
+	copy(construction, colony.bestConstruction)
+
+	return construction
+}
+
+// Replace the heuristics of the colony, e.g. when the costs of a dynamic problem change mid-run.
+// The cached best-so-far cost was computed against the old heuristics, so we recompute it
+// against the new ones; otherwise a best that is now worse could block genuine improvements.
+// The heuristics must have the colony's layout: n x n, or one entry per edge for a SparseProblem
+func (colony *AntColony) UpdateHeuristics(heuristics [][]float64) {
+	colony.heuristics = heuristics
+	colony.zeroDiagonal(colony.heuristics)
+
+	if colony.bestTour != nil {
+		colony.bestCost = colony.tourCost(colony.bestTour)
+	}
+
+	// The best over the whole run, kept when a restart forgets the best-so-far, is reported by BestSolution too
+	if colony.overallTour != nil {
+		colony.overallCost = colony.tourCost(colony.overallTour)
+	}
+
+	if colony.worstTour != nil {
+		colony.worstCost = colony.tourCost(colony.worstTour)
+	}
+
+	// Equal-cost optima may no longer be equal under the new heuristics, so only keep the ones that are still best
+	colony.optima = colony.bestOf(colony.optima)
+
+	// The edge costs changed, so the long edges and the nearest neighbours may have as well
+	if colony.longEdgePenalty {
+		colony.longEdgeThreshold = colony.edgeCostPercentile(colony.longEdgePercentile)
+	}
+
+	if colony.candidateCount > 0 {
+		colony.buildCandidateLists()
+	}
+}
+
+// The cost of traversing a single edge, as given by the problem if it's a CostProblem. Otherwise the heuristic is
+// taken to be the reciprocal of the cost of the edge
+func (colony *AntColony) edgeCost(edge Edge) float64 {
+	if colony.costs != nil {
+		return colony.costs.Cost(edge)
+	}
+
+	return 1.0 / colony.heuristics[edge.A][colony.column(edge)]
+}
+
+// The total cost of a tour
+func (colony *AntColony) tourCost(tour []Edge) float64 {
+	if colony.costFunc != nil {
+		return colony.costFunc(tour)
+	}
+
+	cost := 0.0
+
+	for _, edge := range tour {
+		cost += colony.edgeCost(edge)
+	}
+
+	return cost
+}
+
+// Did this ant construct a complete tour? Ants can get stuck in dead ends on sparse graphs.
+// A complete tour visits every component, and its last edge closes the cycle back to its start
+func (colony *AntColony) IsComplete(ant *Ant) bool {
+	n := len(ant.tour)
+
+	if n == 0 || ant.tour[n-1].B != ant.tour[0].A {
+		return false
+	}
+
+	visited := make(map[uint]bool)
+
+	for _, edge := range ant.tour {
+		visited[edge.A] = true
+	}
+
+	return len(visited) == len(colony.constructionGraph.Nodes)
+}
+
+// The cost of a tour, as used by the colony to compare tours and compute deposits
+func (colony *AntColony) TourCost(tour []Edge) float64 {
+	return colony.tourCost(tour)
+}
+
+// Add amount to the pheromones on every edge of a tour. The amount is clamped to the colony's maximum single
+// deposit, if one is set. If the problem is a ConstrainedProblem, the edges it rejects given the part of the tour
+// before them are skipped
+func (colony *AntColony) DepositTour(tour []Edge, amount float64) {
+	if colony.maxSingleDeposit > 0 {
+		amount = math.Min(amount, colony.maxSingleDeposit)
+	}
+
+	for i, edge := range tour {
+		// Limit the capacity of the prefix, so the problem can't append into the rest of the tour
+		if colony.constraints != nil && !colony.constraints.Feasible(tour[:i:i], edge) {
+			continue
+		}
+
+		colony.forEachDirection(edge, func(e Edge) { colony.Pheromones[e.A][colony.column(e)] += amount })
+	}
+}
+
+// Call update with the edge, and in an undirected graph with its reverse as well: there (a, b) and (b, a) are the
+// same connection, so a change to the trail of one applies to the other. A reverse without a pheromone entry is
+// skipped
+func (colony *AntColony) forEachDirection(edge Edge, update func(e Edge)) {
+	update(edge)
+
+	if colony.constructionGraph.Directed || edge.A == edge.B {
+		return
+	}
+
+	reverse := Edge{A: edge.B, B: edge.A}
+
+	if _, ok := colony.entry(reverse); ok {
+		update(reverse)
+	}
+}
+
+// Undo DepositTour: subtract amount (clamped as DepositTour clamps it) from the pheromones on every edge of a tour,
+// without going below the edge's initial pheromone. The edge usage counts aren't affected
+func (colony *AntColony) withdrawTour(tour []Edge, amount float64) {
+	if colony.maxSingleDeposit > 0 {
+		amount = math.Min(amount, colony.maxSingleDeposit)
+	}
+
+	for _, edge := range tour {
+		colony.forEachDirection(edge, func(e Edge) {
+			tau := colony.Pheromones[e.A][colony.column(e)] - amount
+			colony.Pheromones[e.A][colony.column(e)] = math.Max(tau, colony.initialPheromones[e.A][colony.column(e)])
+		})
+	}
+}
+
+// Account for a tour completed by this ant: record it if it is better than the best tour found so far,
+// or worse than the worst one
+func (colony *AntColony) recordTour(ant *Ant) (float64, bool) {
+	if !colony.IsComplete(ant) {
+		return 0, false
+	}
+
+	cost := colony.tourCost(ant.tour)
+
+	if cost > colony.worstCost {
+		colony.worstTour = make([]Edge, len(ant.tour))
+		copy(colony.worstTour, ant.tour)
+		colony.worstCost = cost
+	}
+
+	if colony.improves(cost, colony.costToBeat()) {
+		colony.setBest(ant.tour, cost)
+		colony.optima = [][]Edge{colony.bestTour}
+	} else if colony.bestTour != nil && colony.ties(cost, colony.bestCost) {
+		colony.handleTie(ant.tour)
+	}
+
+	return cost, true
+}
+
+// The cost a tour has to improve on to become the best-so-far: the best-so-far cost, or the baseline's if it's lower.
+// The baseline itself never becomes the best-so-far, so the strategies don't deposit on it
+func (colony *AntColony) costToBeat() float64 {
+	if colony.baselineTour != nil {
+		return math.Min(colony.bestCost, colony.baselineCost)
+	}
+
+	return colony.bestCost
+}
+
+// Make a copy of a tour the best-so-far
+func (colony *AntColony) setBest(tour []Edge, cost float64) {
+	colony.bestTour = make([]Edge, len(tour))
+	copy(colony.bestTour, tour)
+	colony.bestCost = cost
+	// Keep a separate copy of the moves as they were made, so that later changes
+	// to the best tour don't affect the recorded construction
+	colony.bestConstruction = make([]Edge, len(tour))
+	copy(colony.bestConstruction, tour)
+	colony.recentBests = append(colony.recentBests, colony.bestTour)
+	colony.bestIteration = colony.iterations
+	colony.timeToBest = time.Since(colony.created)
+
+	if len(colony.recentBests) > numRecentBests {
+		colony.recentBests = colony.recentBests[1:]
+	}
+}
+
+// Estimate the expected cost of a tour constructed from the current pheromones and heuristics
+// by constructing samples tours with a scratch ant and averaging the costs of the complete ones.
+// Samples whose ant got stuck are left out, so the estimate is conditioned on the ant finishing its tour.
+// This is read-only: neither the pheromones nor the best-so-far tour are affected.
+// Each sample costs a full tour construction (O(n^2) for a complete graph), so this is
+// as expensive as an iteration with samples ants. Returns NaN if samples is not positive, and +Inf if no
+// sample completed its tour
+func (colony *AntColony) ExpectedTourLength(samples int) float64 {
+	if samples <= 0 {
+		return math.NaN()
+	}
+
+	total := 0.0
+	completed := 0
+
+	for i := 0; i < samples; i++ {
+		ant := colony.newAnt()
+		colony.construct(&ant)
+
+		if colony.IsComplete(&ant) {
+			total += colony.tourCost(ant.tour)
+			completed++
+		}
+	}
+
+	if completed == 0 {
+		return math.Inf(1)
+	}
+
+	return total / float64(completed)
 }
 
 func (colony *AntColony) EvaporatePheromones() {
-	for i := 0; i < len(colony.constructionGraph.Nodes); i++ {
-		for j := 0; j < len(colony.constructionGraph.Nodes); j++ {
-			colony.Pheromones[i][j] *= (rho)
+	for i := range colony.Pheromones {
+		for j := range colony.Pheromones[i] {
+			colony.Pheromones[i][j] *= (1 - colony.rho)
 		}
 	}
 }
 
+// Construct a tour. The ant visits every component exactly once (or, for components with a visit capacity,
+// at most that many times) and then takes a final edge closing the cycle back to its initial location.
+// The initial location is only feasible again for that final edge (unless its capacity allows revisiting it),
+// so without capacities a tour over n components consists of exactly n edges. The closing edge is part of
+// the tour, so its cost is counted by tourCost and deposits
 func (ant *Ant) DoCycle(colony *AntColony) {
-	initLocation := ant.currComponent
-
-	// Our tour should be as long as the number of vertices
-	for len(ant.tour) != len(colony.constructionGraph.Nodes) {
-		ant.memory[ant.currComponent] = true
-		// What is the probability of going to each edge in our neighbourhood?
-		// For simplicity, we also track the probabilities of nodes not in our neighbourhood (and set them to 0)
-		weights := make(map[uint]float64)
-		// We track the sum of the edge scores so that we can normalize by it
-		// and convert it to a valid probability distribution
-		denom := 0.0
-
-		for _, edge := range colony.constructionGraph.Edges[ant.currComponent] {
-			// ant.memory[ant.currComponent] = true
-
-			if !ant.memory[edge.B] && edge.A != edge.B {
-				// The score for this edge is affected by the current amount of pheromones on it
-				// and its heuristic (e.g. in TSP the heuristic is inversely proportional to the weight of the edge)
-				score := math.Pow(colony.Pheromones[edge.A][edge.B], alpha) * math.Pow(colony.heuristics[edge.A][edge.B], beta)
-				weights[edge.B] = score
-				denom += score
-			} else {
-				// If this edge either (1) goes from the current node to itself or (2) the node it goes to has
-				// already been visited, set its probability to 0
-				weights[edge.B] = 0
-			}
-		}
-
-		// Normalize the scores to convert into a valid probability distribution
-		for dest := range weights {
-			weights[dest] /= denom
-		}
-
-		// Sample one of the edges according to the probability distribution
-		dest := weightedSampling(weights)
-		edge := Edge{A: ant.currComponent, B: uint(dest)}
+	// If a prefix is fixed, the ant first follows it before constructing the rest of the tour freely
+	ant.begin(colony)
+
+	for !ant.closed(colony) {
+		candidates, scores := ant.candidates(colony)
+
+		// We are stuck in a dead end, so the tour can't be completed
+		if len(candidates) == 0 {
+			break
+		}
+
+		// Measure how exploitative the choice is before making it
+		ant.recordChoice(scores)
+		// Choose one of the candidates according to the selection method
+		dest := colony.selectNext(ant.random(colony), candidates, scores)
 		// Go through the edge and change our current location
-		ant.currComponent = edge.B
-		ant.tour = append(ant.tour, edge)
-		// If we only have one edge left, we mark the initial location (the start of the cycle)
-		// as unvisited again
-		if len(ant.tour) == len(colony.constructionGraph.Nodes)-1 {
-			ant.memory[initLocation] = false
+		colony.step(ant, Edge{A: ant.currComponent, B: dest})
+	}
+}
+
+// Move an ant along an edge it chose, applying the local pheromone update if one is set. The update only happens
+// while the colony runs an iteration, so sampling tours doesn't change the trails
+func (colony *AntColony) step(ant *Ant, edge Edge) {
+	ant.move(edge)
+
+	if colony.localDecay > 0 && colony.constructing {
+		colony.forEachDirection(edge, func(e Edge) {
+			tau := colony.Pheromones[e.A][colony.column(e)]
+			colony.Pheromones[e.A][colony.column(e)] = (1-colony.localDecay)*tau + colony.localDecay*colony.initialPheromones[e.A][colony.column(e)]
+		})
+	}
+}
+
+// Which components can the ant go to next, and how attractive is each of them?
+// There are no candidates if the ant is stuck in a dead end
+func (ant *Ant) candidates(colony *AntColony) ([]uint, []float64) {
+	edges := colony.restrictToCandidates(ant.feasibleEdges(colony))
+	candidates := make([]uint, 0, len(edges))
+
+	for _, edge := range edges {
+		candidates = append(candidates, edge.B)
+	}
+
+	scores := colony.scores(edges, colony.effectiveBeta(len(ant.tour)))
+	ant.recall(colony, edges, scores)
+	colony.penalizeLongEdges(edges, scores)
+
+	return candidates, scores
+}
+
+// Start a new tour at the current component. If a prefix is fixed, walk along it
+func (ant *Ant) begin(colony *AntColony) {
+	if len(ant.tour) != 0 {
+		return
+	}
+
+	ant.start = ant.currComponent
+	ant.visit(ant.currComponent)
+
+	if len(colony.fixedPrefix) == 0 {
+		return
+	}
+
+	for _, next := range colony.fixedPrefix[1:] {
+		ant.move(Edge{A: ant.currComponent, B: next})
+	}
+}
+
+// Go through an edge, appending it to the tour and visiting its end
+func (ant *Ant) move(edge Edge) {
+	ant.tour = append(ant.tour, edge)
+	ant.currComponent = edge.B
+	ant.visit(edge.B)
+}
+
+// Mark a component as visited once more
+func (ant *Ant) visit(component uint) {
+	if ant.memory[component] == 0 {
+		ant.numVisited++
+	}
+
+	ant.memory[component]++
+}
+
+// Has the ant visited every component and returned to where it started?
+func (ant *Ant) closed(colony *AntColony) bool {
+	return len(ant.tour) > 0 && ant.numVisited == len(colony.constructionGraph.Nodes) && ant.currComponent == ant.start
+}
+
+// The edges the ant may take next. Once every component has been visited, the only feasible move closes the cycle
+// back to the start. Before that, we can't go from the current node to itself or to a node that has used up its
+// visit capacity (a single visit, unless set otherwise with WithVisitCapacity). If the problem is a
+// ConstrainedProblem, it can rule out further edges based on the partial tour
+func (ant *Ant) feasibleEdges(colony *AntColony) []Edge {
+	feasible := make([]Edge, 0)
+	closing := ant.numVisited == len(colony.constructionGraph.Nodes)
+
+	for _, edge := range colony.constructionGraph.Edges[ant.currComponent] {
+		if edge.A == edge.B {
+			continue
+		}
+
+		if closing && edge.B != ant.start {
+			continue
+		}
+
+		if !closing && ant.memory[edge.B] >= colony.visitCapacity(edge.B) {
+			continue
 		}
+
+		// Problems with constraints beyond the visits get the final say
+		if colony.constraints != nil && !colony.constraints.Feasible(ant.tour, edge) {
+			continue
+		}
+
+		feasible = append(feasible, edge)
 	}
+
+	return feasible
 }
 
-func (ant *Ant) DepositPheromones(colony *AntColony) {
-	tourCost := 0.0
+// The components an ant at from may go to next as far as the graph is concerned: the ends of the edges leaving
+// from, except for self-loops and components marked in visited (i.e. that used up their visit capacity).
+// Construction applies further rules that this doesn't: it tracks the visit capacities set with WithVisitCapacity
+// itself, asks a ConstrainedProblem about each edge given the partial tour (which this doesn't have), and narrows
+// the choice to the candidate lists set with WithCandidateLists when it can. To get the final move closing the
+// cycle, mark the start of the tour as unvisited, as construction does.
+// This is read-only, and is useful for custom construction loops or for debugging why an ant got stuck
+func (colony *AntColony) FeasibleNeighbors(from uint, visited []bool) []uint {
+	neighbors := make([]uint, 0)
 
-	for _, edge := range ant.tour {
-		tourCost += 1.0 / colony.heuristics[edge.A][edge.B]
+	if from >= uint(len(colony.constructionGraph.Edges)) {
+		return neighbors
 	}
 
+	for _, edge := range colony.constructionGraph.Edges[from] {
+		if edge.A == edge.B || (edge.B < uint(len(visited)) && visited[edge.B]) {
+			continue
+		}
+
+		neighbors = append(neighbors, edge.B)
+	}
+
+	return neighbors
+}
+
+// How many times may an ant visit a component?
+func (colony *AntColony) visitCapacity(component uint) int {
+	if capacity, ok := colony.capacities[component]; ok {
+		return capacity
+	}
+
+	return 1
+}
+
+// The scores of the candidate edges, with the heuristics raised to the power of beta. Scores are only meaningful
+// relative to each other, since they're normalized into probabilities. In log-space mode we compute
+// alpha * log(tau) + beta * log(eta) and subtract the largest value before exponentiating, so the best candidate
+// gets a score of 1 and nothing can overflow
+func (colony *AntColony) scores(edges []Edge, beta float64) []float64 {
+	scores := make([]float64, len(edges))
+
+	for i, edge := range edges {
+		scores[i] = colony.rawScore(edge, beta)
+	}
+
+	if !colony.logSpace {
+		return scores
+	}
+
+	maxScore := math.Inf(-1)
+
+	for i := range scores {
+		maxScore = math.Max(maxScore, scores[i])
+	}
+
+	for i := range scores {
+		// If every score vanished, we leave them all at 0
+		if math.IsInf(maxScore, -1) {
+			scores[i] = 0
+		} else {
+			scores[i] = math.Exp(scores[i] - maxScore)
+		}
+	}
+
+	return scores
+}
+
+// The beta used for an ant's next step, once it has taken steps edges. With WithProgressiveBeta the heuristic's
+// influence decays linearly with the fraction of the tour constructed, p = steps / n: beta * (1 - (1 - floor) * p),
+// from the full beta at the first step down to nearly floor * beta at the last one
+func (colony *AntColony) effectiveBeta(steps int) float64 {
+	if !colony.progressiveBeta {
+		return colony.beta
+	}
+
+	progress := float64(steps) / float64(len(colony.constructionGraph.Nodes))
+
+	return colony.beta * (1 - (1-colony.betaFloor)*math.Min(progress, 1))
+}
+
+// An edge's score, or its logarithm in log-space mode. While the ants construct with batched scoring, it's
+// looked up in the precomputed table instead
+func (colony *AntColony) rawScore(edge Edge, beta float64) float64 {
+	if colony.scoresBatched {
+		return colony.scoreTable[edge.A][colony.column(edge)]
+	}
+
+	if colony.logSpace {
+		return colony.logScore(edge, beta)
+	}
+
+	return colony.score(edge, beta)
+}
+
+// The logarithm of an edge's score, which doesn't over- or underflow even when the score itself would
+func (colony *AntColony) logScore(edge Edge, beta float64) float64 {
+	logScore := beta * math.Log(colony.heuristics[edge.A][colony.column(edge)])
+
+	if !colony.pheromoneDisabled {
+		logScore += colony.alpha * math.Log(colony.perceivedPheromone(edge))
+	}
+
+	return logScore
+}
+
+// The score for an edge is affected by the current amount of pheromones on it and its heuristic
+// (e.g. in TSP the heuristic is inversely proportional to the weight of the edge)
+func (colony *AntColony) score(edge Edge, beta float64) float64 {
+	// With the pheromones disabled, only the heuristic matters. We skip the pheromone factor entirely
+	// rather than raising it to the power of 0, which is wasteful and treats a zero pheromone as 1
+	if colony.pheromoneDisabled {
+		return math.Pow(colony.heuristics[edge.A][colony.column(edge)], beta)
+	}
+
+	return math.Pow(colony.perceivedPheromone(edge), colony.alpha) * math.Pow(colony.heuristics[edge.A][colony.column(edge)], beta)
+}
+
+// The pheromone an ant perceives on an edge. With perception noise, the actual pheromone is only read with
+// probability perception; otherwise the ant sees the edge's initial pheromone, as if no ant had ever been there
+func (colony *AntColony) perceivedPheromone(edge Edge) float64 {
+	if colony.perception < 1 && colony.rng.Float64() >= colony.perception {
+		return colony.initialPheromones[edge.A][colony.column(edge)]
+	}
+
+	return colony.Pheromones[edge.A][colony.column(edge)]
+}
+
+// Bias the scores towards the edges of the ant's last complete tour, multiplying them by 1 + w, where w is the
+// weight set with WithAntMemory. Unlike the pheromones, this reflects only what this ant has found
+func (ant *Ant) recall(colony *AntColony, edges []Edge, scores []float64) {
+	if colony.memoryWeight == 0 {
+		return
+	}
+
+	for i, edge := range edges {
+		if ant.lastTour[edge] {
+			scores[i] *= 1 + colony.memoryWeight
+		}
+	}
+}
+
+// Multiply the scores of the candidate edges that are longer than the threshold set with WithLongEdgePenalty
+// by the penalty factor
+func (colony *AntColony) penalizeLongEdges(edges []Edge, scores []float64) {
+	if !colony.longEdgePenalty {
+		return
+	}
+
+	for i, edge := range edges {
+		if colony.edgeCost(edge) > colony.longEdgeThreshold {
+			scores[i] *= colony.longEdgeFactor
+		}
+	}
+}
+
+// The given percentile (in (0, 100]) of the costs of all the edges of the graph other than self-loops, using the
+// nearest-rank method: the smallest cost such that at least percentile% of the edges cost no more than it
+func (colony *AntColony) edgeCostPercentile(percentile float64) float64 {
+	costs := make([]float64, 0)
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A != edge.B {
+				costs = append(costs, colony.edgeCost(edge))
+			}
+		}
+	}
+
+	if len(costs) == 0 {
+		return math.Inf(1)
+	}
+
+	sort.Float64s(costs)
+	rank := int(math.Ceil(percentile / 100 * float64(len(costs))))
+
+	return costs[max(rank, 1)-1]
+}
+
+// Remember the edges of the current tour for the following iterations
+func (ant *Ant) remember() {
+	ant.lastTour = make(map[Edge]bool, len(ant.tour))
+
 	for _, edge := range ant.tour {
-		colony.Pheromones[edge.A][edge.B] += 1.0 / tourCost
+		ant.lastTour[edge] = true
+	}
+}
+
+// Construct a tour greedily: from each component, go to the feasible neighbour with the highest heuristic
+// (e.g. the nearest neighbour in TSP). Ties are broken in favour of the neighbour with the lowest index, so the
+// tour doesn't depend on the order of the graph's edges
+func (ant *Ant) greedyCycle(colony *AntColony) {
+	ant.begin(colony)
+
+	for !ant.closed(colony) {
+		edges := ant.feasibleEdges(colony)
+
+		// We are stuck in a dead end, so the tour can't be completed
+		if len(edges) == 0 {
+			break
+		}
+
+		best := edges[0]
+
+		for _, edge := range edges[1:] {
+			h, bestH := colony.heuristics[edge.A][colony.column(edge)], colony.heuristics[best.A][colony.column(best)]
+
+			if h > bestH || (h == bestH && edge.B < best.B) {
+				best = edge
+			}
+		}
+
+		ant.move(best)
 	}
 }
 
+func (ant *Ant) DepositPheromones(colony *AntColony) {
+	// An incomplete tour has no meaningful cost
+	if !colony.IsComplete(ant) {
+		return
+	}
+
+	amount := 1.0 / colony.tourCost(ant.tour)
+
+	// In the hyper-cube framework all the ants' deposits on an edge add up to at most rho
+	if colony.hyperCube && colony.depositNorm > 0 {
+		amount *= colony.rho / colony.depositNorm
+	}
+
+	colony.DepositTour(ant.tour, amount)
+}
+
+// The tour constructed by the ant in the current iteration, which is incomplete if the ant got stuck.
+// The returned slice belongs to the ant and must not be modified
+func (ant *Ant) Tour() []Edge {
+	return ant.tour
+}
+
 func (ant *Ant) ResetSolution(colony *AntColony) {
-	ant.memory = make(map[uint]bool)
-	ant.currComponent = uint(rand.Intn(len(colony.constructionGraph.Nodes)))
+	if colony.memoryWeight > 0 && colony.IsComplete(ant) {
+		ant.remember()
+	}
+
+	ant.memory = make(map[uint]int)
+	ant.numVisited = 0
+	ant.currComponent = colony.startComponent(ant.random(colony))
 	ant.tour = make([]Edge, 0)
 }
 
-// Sample from a discrete distribution where the probability of sampling v_i is p_i: P(v_i) = p_i
-func weightedSampling(weights map[uint]float64) int {
-	// Generate a random number 0 <= x < 1
-	x := rand.Float64()
-	// Sort the map by probability
-	type KeyValue struct {
-		idx    uint
-		weight float64
+// Check that a matrix is n x n, naming it in the errors
+func validateSquare(matrix [][]float64, n int, name string) error {
+	if len(matrix) != n {
+		return fmt.Errorf("%w: %s matrix has %d rows, expected %d", ErrRaggedMatrix, name, len(matrix), n)
 	}
 
-	kvs := make([]KeyValue, 0, len(weights))
+	for i, row := range matrix {
+		if len(row) != n {
+			return fmt.Errorf("%w: %s matrix row %d has %d entries, expected %d", ErrRaggedMatrix, name, i, len(row), n)
+		}
+	}
+
+	return nil
+}
 
-	for idx, weight := range weights {
-		kvs = append(kvs, KeyValue{idx, weight})
+// Check that the heuristics have the colony's layout, and that their entries on the edges of the graph other than
+// self-loops are finite and non-negative. A heuristic of 0 is allowed, and makes the ants avoid the edge while they
+// have a choice; a negative one (e.g. from a negative weight) would make the selection probabilities meaningless
+func (colony *AntColony) validateHeuristics(heuristics [][]float64) error {
+	if err := colony.validateLayout(heuristics, "heuristic"); err != nil {
+		return err
 	}
 
-	sort.Slice(kvs, func(i, j int) bool { return kvs[i].weight > kvs[j].weight })
-	// Track culminative probability
-	culm := 0.0
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A == edge.B {
+				continue
+			}
+
+			eta := heuristics[edge.A][colony.column(edge)]
 
-	for _, kv := range kvs {
-		if culm < x && x < culm+kv.weight {
-			return int(kv.idx)
+			if !(eta >= 0) || math.IsInf(eta, 0) {
+				return fmt.Errorf("%w: heuristic on edge (%d, %d) must be finite and non-negative, got %v", ErrInvalidParams, edge.A, edge.B, eta)
+			}
 		}
+	}
 
-		culm += kv.weight
+	return nil
+}
+
+// Check that the initial pheromones have the colony's layout, and that the pheromone on every edge of the graph
+// (other than self-loops, which are never taken) is finite and strictly positive
+func (colony *AntColony) validatePheromones(pheromones [][]float64) error {
+	if err := colony.validateLayout(pheromones, "initial pheromone"); err != nil {
+		return err
 	}
 
-	return 0
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A == edge.B {
+				continue
+			}
+
+			tau := pheromones[edge.A][colony.column(edge)]
+
+			if !(tau > 0) || math.IsInf(tau, 0) {
+				return fmt.Errorf("%w: initial pheromone on edge (%d, %d) must be finite and strictly positive, got %v", ErrInvalidParams, edge.A, edge.B, tau)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Overwrite the initial pheromones with the pheromones of the colony set with WithTransferFrom, on every edge both
+// colonies have an entry for where the transferred pheromone is finite and positive. The two colonies may store
+// their matrices in different layouts
+func (colony *AntColony) applyTransfer() {
+	source := colony.transferSource
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			j, ok := source.entry(edge)
+
+			if !ok {
+				continue
+			}
+
+			if tau := source.Pheromones[edge.A][j]; tau > 0 && !math.IsInf(tau, 0) {
+				colony.Pheromones[edge.A][colony.column(edge)] = tau
+			}
+		}
+	}
+}
+
+// Copy a matrix, so that the copy can be modified without affecting the original
+func copyMatrix(matrix [][]float64) [][]float64 {
+	res := make([][]float64, len(matrix))
+
+	for i := range matrix {
+		res[i] = make([]float64, len(matrix[i]))
+		copy(res[i], matrix[i])
+	}
+
+	return res
+}
+
+// Self-loops are never taken, but a problem may still compute a (possibly huge) heuristic
+// for them, e.g. 1 / (0 + eps). Zero the diagonal so it can never dominate a selection; in the sparse layout,
+// that's the entries of the self-loops among the edges
+func (colony *AntColony) zeroDiagonal(matrix [][]float64) {
+	if colony.columns != nil {
+		for _, edges := range colony.constructionGraph.Edges {
+			for j, edge := range edges {
+				if edge.A == edge.B && j < len(matrix[edge.A]) {
+					matrix[edge.A][j] = 0
+				}
+			}
+		}
+
+		return
+	}
+
+	for i := range matrix {
+		if i < len(matrix[i]) {
+			matrix[i][i] = 0
+		}
+	}
 }
@@ -0,0 +1,695 @@
+package antcolony
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// A problem given directly by its graph and matrices
+type matrixProblem struct {
+	graph      Graph
+	pheromones [][]float64
+	heuristics [][]float64
+}
+
+func (problem *matrixProblem) ConstructGraph() Graph {
+	return problem.graph
+}
+
+func (problem *matrixProblem) InitPheromones(num_ants uint) [][]float64 {
+	return problem.pheromones
+}
+
+func (problem *matrixProblem) InitHeuristics() [][]float64 {
+	return problem.heuristics
+}
+
+// A directed ring 0 -> 1 -> ... -> n-1 -> 0, the sparsest strongly connected graph
+func directedRing(n int) Graph {
+	graph := Graph{Nodes: make([]uint, n), Edges: make([][]Edge, n), Directed: true}
+
+	for i := 0; i < n; i++ {
+		graph.Nodes[i] = uint(i)
+		graph.Edges[i] = []Edge{{A: uint(i), B: uint((i + 1) % n)}}
+	}
+
+	return graph
+}
+
+// An n x n matrix with every entry set to value
+func filledMatrix(n int, value float64) [][]float64 {
+	matrix := make([][]float64, n)
+
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+
+		for j := range matrix[i] {
+			matrix[i][j] = value
+		}
+	}
+
+	return matrix
+}
+
+func TestNewAntColonyRejectsRaggedMatrices(t *testing.T) {
+	ragged := [][]float64{{0, 1}, {0, 0, 1}, {1}}
+
+	tests := []struct {
+		name       string
+		pheromones [][]float64
+		heuristics [][]float64
+	}{
+		{"ragged pheromones", ragged, filledMatrix(3, 1)},
+		{"ragged heuristics", filledMatrix(3, 1), ragged},
+		{"too few pheromone rows", filledMatrix(3, 1)[:2], filledMatrix(3, 1)},
+		{"too many heuristic rows", filledMatrix(3, 1), filledMatrix(4, 1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: directedRing(3), pheromones: test.pheromones, heuristics: test.heuristics}
+			_, err := NewAntColony(problem)
+
+			if !errors.Is(err, ErrRaggedMatrix) {
+				t.Fatalf("got error %v, expected %v", err, ErrRaggedMatrix)
+			}
+		})
+	}
+}
+
+func TestNewAntColonyAcceptsSquareMatricesOnSparseGraphs(t *testing.T) {
+	problem := &matrixProblem{graph: directedRing(3), pheromones: filledMatrix(3, 1), heuristics: filledMatrix(3, 1)}
+	colony, err := NewAntColony(problem, WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(1)
+
+	if _, cost := colony.BestSolution(); cost != 3 {
+		t.Errorf("got cost %v, expected 3", cost)
+	}
+}
+
+func TestNewAntColonyRejectsEmptyGraphs(t *testing.T) {
+	problem := &matrixProblem{graph: Graph{}}
+
+	if _, err := NewAntColony(problem); !errors.Is(err, ErrInvalidGraph) {
+		t.Fatalf("got error %v, expected %v", err, ErrInvalidGraph)
+	}
+}
+
+func TestAntCountScheduleKeepsAtLeastOneAnt(t *testing.T) {
+	weights := ringWeights(t, 6)
+	tests := []struct {
+		name     string
+		schedule func(iter int) int
+	}{
+		{"zero", func(iter int) int { return 0 }},
+		{"negative", func(iter int) int { return -3 }},
+		{"shrinking to zero", func(iter int) int { return 2 - iter }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAntCountSchedule(test.schedule))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(3)
+
+			if len(colony.ants) != 1 {
+				t.Errorf("got %d ants, expected 1", len(colony.ants))
+			}
+
+			if got := colony.ToursConstructed(); got < 3 {
+				t.Errorf("got %d tours in 3 iterations, expected at least 3", got)
+			}
+
+			// Used to panic on an empty pool
+			colony.GetSolution()
+		})
+	}
+}
+
+func TestGetSolutionLeavesTheAntsUntouched(t *testing.T) {
+	weights := ringWeights(t, 6)
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(3))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(2)
+	colony.GetSolution()
+
+	for i := range colony.ants {
+		if len(colony.ants[i].tour) != 0 {
+			t.Errorf("ant %d has a tour of %d edges after GetSolution, expected a fresh ant", i, len(colony.ants[i].tour))
+		}
+	}
+}
+
+func TestGetSolutionReportsTheTourCost(t *testing.T) {
+	weights := ringWeights(t, 6)
+
+	for _, num_ants := range []int{0, 1} {
+		colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAntCountSchedule(func(iter int) int { return num_ants }))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		colony.RunSimulation(2)
+		solution := colony.GetSolution()
+
+		if err := ValidateTour(solution.Tour, 6); err != nil {
+			t.Fatalf("pool of %d: %v", num_ants, err)
+		}
+
+		if expected := SumCost(weights)(solution.Tour); solution.Cost != expected {
+			t.Errorf("pool of %d: got cost %v, expected %v", num_ants, solution.Cost, expected)
+		}
+	}
+}
+
+// Adds the edges to a copy of the graph
+func withEdges(graph Graph, edges ...Edge) Graph {
+	graph = graph.clone()
+
+	for _, edge := range edges {
+		graph.Edges[edge.A] = append(graph.Edges[edge.A], edge)
+	}
+
+	return graph
+}
+
+func TestExpectedTourLengthSkipsStuckAnts(t *testing.T) {
+	// The only tours are the ring 0 -> 1 -> 2 -> 3 -> 0 and its rotations. An ant that takes the chord 1 -> 3
+	// (or starts at 3 and takes 3 -> 1) gets stuck with a partial tour, which must not lower the estimate
+	stuck := withEdges(directedRing(4), Edge{A: 1, B: 3}, Edge{A: 3, B: 1})
+	// A star has no Hamiltonian cycle at all
+	star := Graph{
+		Nodes:    []uint{0, 1, 2, 3},
+		Edges:    [][]Edge{{{A: 0, B: 1}, {A: 0, B: 2}, {A: 0, B: 3}}, {{A: 1, B: 0}}, {{A: 2, B: 0}}, {{A: 3, B: 0}}},
+		Directed: true,
+	}
+
+	tests := []struct {
+		name     string
+		graph    Graph
+		samples  int
+		expected float64
+	}{
+		{"some ants get stuck", stuck, 200, 4},
+		{"every ant gets stuck", star, 20, math.Inf(1)},
+		{"no samples", stuck, 0, math.NaN()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: test.graph, pheromones: filledMatrix(4, 1), heuristics: filledMatrix(4, 1)}
+			colony, err := NewAntColony(problem, WithSeed(1))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := colony.ExpectedTourLength(test.samples)
+
+			if got != test.expected && !(math.IsNaN(got) && math.IsNaN(test.expected)) {
+				t.Errorf("got %v, expected %v", got, test.expected)
+			}
+		})
+	}
+}
+
+func TestEdgeUsageCountsOnlyTheAntsTours(t *testing.T) {
+	weights := ringWeights(t, 5)
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"ant cycle", nil},
+		{"elitist", []Option{WithPheromoneStrategy(ElitistStrategy{})}},
+		{"rank", []Option{WithPheromoneStrategy(RankStrategy{})}},
+		{"mmas", []Option{WithPheromoneStrategy(MaxMinStrategy{})}},
+		{"restart bias", []Option{WithRestartOnStagnation(1), WithRestartBias(1)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := append([]Option{WithSeed(1), WithAnts(3)}, test.opts...)
+			colony, err := NewAntColony(NewTSPProblem(weights), opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(4)
+			total := uint(0)
+
+			for _, row := range colony.EdgeUsage() {
+				for _, count := range row {
+					total += count
+				}
+			}
+
+			// Every tour on a complete graph is complete, and has one edge per city
+			if expected := uint(colony.ToursConstructed() * 5); total != expected {
+				t.Errorf("got %d uses, expected %d", total, expected)
+			}
+		})
+	}
+}
+
+func TestUpdateHeuristicsRecomputesTheBestCost(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"without restarts", nil},
+		// The restarts forget the best-so-far, so BestSolution reports the best over the whole run
+		{"with restarts forgetting the best", []Option{WithRestartOnStagnation(1), WithRestartKeepBest(false)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: NewCompleteGraph(4), pheromones: filledMatrix(4, 1), heuristics: filledMatrix(4, 1)}
+			colony, err := NewAntColony(problem, append([]Option{WithSeed(1)}, test.opts...)...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(2)
+
+			if _, cost := colony.BestSolution(); cost != 4 {
+				t.Fatalf("got cost %v before the change, expected 4", cost)
+			}
+
+			// Every edge now costs 2, so the old best costs 8 and mustn't block the (equally long) new tours
+			colony.UpdateHeuristics(filledMatrix(4, 0.5))
+
+			for _, iters := range []int{0, 3} {
+				colony.RunSimulation(iters)
+				tour, cost := colony.BestSolution()
+
+				if cost != 8 || colony.TourCost(tour) != cost {
+					t.Errorf("after %d more iterations: got best cost %v for a tour costing %v, expected 8", iters, cost, colony.TourCost(tour))
+				}
+
+				if _, worst := colony.WorstTour(); worst != 8 {
+					t.Errorf("after %d more iterations: got worst cost %v, expected 8", iters, worst)
+				}
+			}
+		})
+	}
+}
+
+func TestHeuristicDiagonalNeverContributes(t *testing.T) {
+	heuristics := filledMatrix(5, 1)
+
+	// A huge heuristic on the self-loops, as 1 / (0 + eps) would give, must never attract an ant
+	for i := range heuristics {
+		heuristics[i][i] = 1e12
+	}
+
+	problems := map[string]ACOptimizable{
+		"matrix": &matrixProblem{graph: NewCompleteGraph(5), pheromones: filledMatrix(5, 1), heuristics: heuristics},
+		"tsp":    NewTSPProblem(ringWeights(t, 5)),
+	}
+
+	for name, problem := range problems {
+		t.Run(name, func(t *testing.T) {
+			colony, err := NewAntColony(problem, WithSeed(1))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range colony.heuristics {
+				if colony.heuristics[i][i] != 0 {
+					t.Errorf("heuristic of self-loop %d is %v, expected 0", i, colony.heuristics[i][i])
+				}
+			}
+
+			for _, result := range colony.Sample(50) {
+				for _, edge := range result.Tour {
+					if edge.A == edge.B {
+						t.Fatalf("tour %v takes a self-loop", result.Tour)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLargeIndicesSurviveTheIndexMath(t *testing.T) {
+	const n = 1500
+	problem := &matrixProblem{graph: directedRing(n), pheromones: filledMatrix(n, 1), heuristics: filledMatrix(n, 1)}
+	colony, err := NewAntColony(problem, WithSeed(1), WithAnts(8))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(2)
+	tour, cost := colony.BestSolution()
+
+	if err := ValidateTour(tour, n); err != nil {
+		t.Fatal(err)
+	}
+
+	if cost != n {
+		t.Errorf("got cost %v, expected %v", cost, n)
+	}
+
+	// The order, successor and canonical forms must round-trip without truncating the indices
+	order := TourOrder(tour)
+	succ, err := OrderSuccessors(order)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, next := range succ {
+		if next != uint((i+1)%n) {
+			t.Fatalf("got successor %d for %d, expected %d", next, i, (i+1)%n)
+		}
+	}
+
+	if canonical := CanonicalTour(tour); canonical[0] != 0 || canonical[n-1] != n-1 {
+		t.Errorf("got canonical tour from %d to %d, expected from 0 to %d", canonical[0], canonical[n-1], n-1)
+	}
+
+	// Starts are drawn over the whole index range
+	starts := make(map[uint]bool)
+
+	for i := 0; i < 200; i++ {
+		starts[colony.startComponent(colony.rng)] = true
+	}
+
+	if len(starts) < 150 {
+		t.Errorf("got %d distinct starts in 200 draws over %d components", len(starts), n)
+	}
+}
+
+func TestDisabledPheromoneOnlyFollowsTheHeuristics(t *testing.T) {
+	weights := ringWeights(t, 7)
+	heuristics := NewTSPProblem(weights).InitHeuristics()
+	skewed := filledMatrix(7, 1)
+
+	// Trails that would pull the ants very differently if they counted
+	for i := range skewed {
+		for j := range skewed[i] {
+			skewed[i][j] = float64(1 + (i*7+j)%5*100)
+		}
+	}
+
+	samples := make([][]TourResult, 0, 2)
+
+	for _, pheromones := range [][][]float64{filledMatrix(7, 1), skewed} {
+		problem := &matrixProblem{graph: NewCompleteGraph(7), pheromones: pheromones, heuristics: heuristics}
+		colony, err := NewAntColony(problem, WithSeed(1), WithPheromoneDisabled(true))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		samples = append(samples, colony.Sample(30))
+	}
+
+	if !reflect.DeepEqual(samples[0], samples[1]) {
+		t.Error("the sampled tours depend on the pheromones")
+	}
+}
+
+func TestToursCloseTheCycleAndCountTheClosingEdge(t *testing.T) {
+	const n = 6
+	// Every tour enters component 0 exactly once, through an expensive edge. For the ants starting at 0 that's the
+	// closing edge, so leaving it out of the cost would make their tours look 100 cheaper
+	weights := filledMatrix(n, 1)
+
+	for i := range weights {
+		weights[i][i] = 0
+		weights[i][0] = 100
+	}
+
+	weights[0][0] = 0
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startsAtZero := 0
+
+	for _, result := range colony.Sample(100) {
+		tour := result.Tour
+
+		if len(tour) != n || tour[n-1].B != tour[0].A {
+			t.Fatalf("tour %v doesn't close back to its start", tour)
+		}
+
+		if tour[0].A == 0 {
+			startsAtZero++
+		}
+
+		if result.Cost != n-1+100 {
+			t.Errorf("tour %v costs %v, expected %v", tour, result.Cost, n-1+100)
+		}
+	}
+
+	if startsAtZero == 0 {
+		t.Error("no tour started at 0, so the closing edge wasn't exercised")
+	}
+}
+
+func TestLogSpaceScoresSurviveOverflow(t *testing.T) {
+	const n = 5
+	// The ring edges i->i+1 are 10 times as attractive as the rest, but with beta = 5 both raise to far beyond
+	// the largest float64, so the naive scores can't tell them apart
+	heuristics := filledMatrix(n, 1e199)
+
+	for i := 0; i < n; i++ {
+		heuristics[i][(i+1)%n] = 1e200
+	}
+
+	problem := &matrixProblem{graph: NewCompleteGraph(n), pheromones: filledMatrix(n, 1), heuristics: heuristics}
+	colony, err := NewAntColony(problem, WithSeed(1), WithBeta(5), WithLogSpaceScores(true))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if score := colony.score(Edge{A: 0, B: 1}, 5); !math.IsInf(score, 1) {
+		t.Fatalf("naive score is %v, expected the instance to overflow it", score)
+	}
+
+	scores := colony.scores([]Edge{{A: 0, B: 1}, {A: 0, B: 2}}, 5)
+
+	if scores[0] != 1 || math.Abs(scores[1]-1e-5) > 1e-12 {
+		t.Fatalf("got log-space scores %v, expected [1 1e-5]", scores)
+	}
+
+	for _, result := range colony.Sample(50) {
+		if len(result.Tour) != n {
+			t.Fatalf("tour %v is incomplete", result.Tour)
+		}
+
+		for _, edge := range result.Tour {
+			if edge.B != (edge.A+1)%n {
+				t.Errorf("tour %v leaves the ring at %v", result.Tour, edge)
+			}
+		}
+	}
+}
+
+func TestNewAntColonyRejectsVanishingPheromones(t *testing.T) {
+	const n = 4
+	withEntry := func(i, j int, value float64) [][]float64 {
+		pheromones := filledMatrix(n, 1)
+		pheromones[i][j] = value
+
+		return pheromones
+	}
+
+	tests := []struct {
+		name       string
+		pheromones [][]float64
+		err        error
+	}{
+		{"zero matrix", filledMatrix(n, 0), ErrInvalidParams},
+		{"one zero edge", withEntry(1, 2, 0), ErrInvalidParams},
+		{"negative edge", withEntry(2, 1, -1), ErrInvalidParams},
+		{"NaN edge", withEntry(0, 3, math.NaN()), ErrInvalidParams},
+		{"infinite edge", withEntry(3, 0, math.Inf(1)), ErrInvalidParams},
+		{"zero diagonal", withEntry(2, 2, 0), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: NewCompleteGraph(n), pheromones: test.pheromones, heuristics: filledMatrix(n, 1)}
+
+			if _, err := NewAntColony(problem); !errors.Is(err, test.err) {
+				t.Errorf("got %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestAntMemoryBiasesTowardsTheLastTour(t *testing.T) {
+	remembered := Edge{A: 0, B: 1}
+	edges := []Edge{remembered, {A: 0, B: 2}, {A: 0, B: 3}}
+
+	tests := []struct {
+		name     string
+		weight   float64
+		expected []float64
+	}{
+		{"disabled", 0, []float64{1, 1, 1}},
+		{"weight 3", 3, []float64{4, 1, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colony := &AntColony{memoryWeight: test.weight}
+			ant := &Ant{lastTour: map[Edge]bool{remembered: true}}
+			scores := []float64{1, 1, 1}
+			ant.recall(colony, edges, scores)
+
+			if !reflect.DeepEqual(scores, test.expected) {
+				t.Errorf("got scores %v, expected %v", scores, test.expected)
+			}
+		})
+	}
+}
+
+func TestAntMemoryRepeatsTheLastTour(t *testing.T) {
+	const n = 6
+	// Without pheromones and with uniform heuristics, only the memory sets the edges apart
+	problem := &matrixProblem{graph: NewCompleteGraph(n), pheromones: filledMatrix(n, 1), heuristics: filledMatrix(n, 1)}
+
+	tests := []struct {
+		name    string
+		weight  float64
+		repeats bool
+	}{
+		{"negligible weight", 1e-9, false},
+		{"overwhelming weight", 1e9, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colony, err := NewAntColony(problem, WithSeed(1), WithAnts(1), WithPheromoneDisabled(true), WithAntMemory(test.weight))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(1)
+			first := colony.ants[0].lastTour
+			repeats := true
+
+			for iter := 0; iter < 20; iter++ {
+				colony.RunSimulation(1)
+				// The ant may start elsewhere, but walking the same cycle in the same direction takes the same edges
+				repeats = repeats && reflect.DeepEqual(colony.ants[0].lastTour, first)
+			}
+
+			if len(first) != n || repeats != test.repeats {
+				t.Errorf("remembered %v first, repeated it every time: %v, expected %v", first, repeats, test.repeats)
+			}
+		})
+	}
+}
+
+func TestMaxSingleDepositClampsDeposits(t *testing.T) {
+	const n = 4
+	tour := []Edge{{A: 0, B: 1}, {A: 1, B: 2}, {A: 2, B: 3}, {A: 3, B: 0}}
+
+	tests := []struct {
+		name     string
+		graph    Graph
+		opts     []Option
+		amount   float64
+		expected float64
+	}{
+		{"below the cap", directedRing(n), []Option{WithMaxSingleDeposit(2)}, 0.5, 1.5},
+		{"above the cap", directedRing(n), []Option{WithMaxSingleDeposit(2)}, 100, 3},
+		{"without a cap", directedRing(n), nil, 100, 101},
+		{"undirected above the cap", NewCompleteGraph(n), []Option{WithMaxSingleDeposit(2)}, 100, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: test.graph, pheromones: filledMatrix(n, 1), heuristics: filledMatrix(n, 1)}
+			colony, err := NewAntColony(problem, test.opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.DepositTour(tour, test.amount)
+
+			for _, edge := range tour {
+				if tau := colony.Pheromones[edge.A][edge.B]; tau != test.expected {
+					t.Errorf("pheromone on %v is %v, expected %v", edge, tau, test.expected)
+				}
+
+				if reverse := colony.Pheromones[edge.B][edge.A]; !test.graph.Directed && reverse != test.expected {
+					t.Errorf("pheromone on the reverse of %v is %v, expected %v", edge, reverse, test.expected)
+				}
+			}
+
+			// Withdrawing the same amount is clamped the same way, restoring the initial trails
+			colony.withdrawTour(tour, test.amount)
+
+			if !reflect.DeepEqual(colony.Pheromones, filledMatrix(n, 1)) {
+				t.Errorf("withdrawing left %v", colony.Pheromones)
+			}
+		})
+	}
+}
+
+func TestResetReappliesTheGreedySeeds(t *testing.T) {
+	weights := randomSymmetricWeights(rand.New(rand.NewSource(1)), 7)
+	newColony := func() *AntColony {
+		colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(5), WithGreedySeeds(true))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return colony
+	}
+
+	reset := newColony()
+	reset.RunSimulation(5)
+	reset.Reset()
+	fresh := newColony()
+
+	// In the first iteration the ants deposit on their greedy tours without constructing, so it draws nothing
+	// from the random sources, which have moved on in the reset colony
+	for _, colony := range []*AntColony{reset, fresh} {
+		colony.RunSimulation(1)
+	}
+
+	resetTour, resetCost := reset.BestSolution()
+	freshTour, freshCost := fresh.BestSolution()
+
+	if !reflect.DeepEqual(resetTour, freshTour) || resetCost != freshCost {
+		t.Errorf("got best %v costing %v after a reset, expected %v costing %v", resetTour, resetCost, freshTour, freshCost)
+	}
+
+	if !reflect.DeepEqual(reset.Pheromones, fresh.Pheromones) {
+		t.Errorf("got trails %v after a reset, expected %v", reset.Pheromones, fresh.Pheromones)
+	}
+}
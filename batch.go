@@ -0,0 +1,65 @@
+package antcolony
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Precompute the score of every edge before the ants construct their tours, so that each construction step
+// only looks the scores up. In Ant System the pheromones don't change while the ants construct their tours,
+// so every ant sees the same scores throughout the iteration, and computing them once saves the repeated
+// exponentiations, which dominate the construction of large instances. The rows are split between goroutines,
+// and each row is computed in a single loop over the pheromone and heuristic rows, whose layout the table shares.
+// The table is only valid for a single iteration, and doesn't apply when the scores change during construction,
+// e.g. with perception noise, progressive beta, or a strategy that updates the pheromones online as in ACS
+func (colony *AntColony) buildScoreTable() {
+	n := len(colony.constructionGraph.Nodes)
+
+	if len(colony.scoreTable) != n {
+		colony.scoreTable = make([][]float64, n)
+
+		for i := range colony.scoreTable {
+			colony.scoreTable[i] = make([]float64, colony.rowLength(i))
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func(first int) {
+			defer wg.Done()
+
+			for i := first; i < n; i += workers {
+				colony.scoreRow(i)
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	colony.scoresBatched = true
+}
+
+// Compute the scores of all the edges leaving a component, the same way score and logScore do
+func (colony *AntColony) scoreRow(i int) {
+	row := colony.scoreTable[i]
+	pheromones := colony.Pheromones[i]
+	heuristics := colony.heuristics[i]
+
+	for j := range row {
+		if colony.logSpace {
+			row[j] = colony.beta * math.Log(heuristics[j])
+
+			if !colony.pheromoneDisabled {
+				row[j] += colony.alpha * math.Log(pheromones[j])
+			}
+		} else if colony.pheromoneDisabled {
+			row[j] = math.Pow(heuristics[j], colony.beta)
+		} else {
+			row[j] = math.Pow(pheromones[j], colony.alpha) * math.Pow(heuristics[j], colony.beta)
+		}
+	}
+}
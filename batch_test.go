@@ -0,0 +1,42 @@
+package antcolony
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// A colony on a random 400-city instance, large enough for the exponentiations to dominate
+func benchmarkColony(b *testing.B) *AntColony {
+	b.Helper()
+	colony, err := NewAntColony(NewTSPProblem(randomSymmetricWeights(rand.New(rand.NewSource(1)), 400)), WithSeed(1))
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return colony
+}
+
+// Scoring every edge once, as the table of batched scoring does at the start of each iteration
+func BenchmarkScores(b *testing.B) {
+	colony := benchmarkColony(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		colony.buildScoreTable()
+	}
+}
+
+// Scoring every edge once, one edge at a time as the construction steps do without batched scoring
+func BenchmarkScore(b *testing.B) {
+	colony := benchmarkColony(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, edges := range colony.constructionGraph.Edges {
+			for _, edge := range edges {
+				colony.score(edge, colony.beta)
+			}
+		}
+	}
+}
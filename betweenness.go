@@ -0,0 +1,112 @@
+package antcolony
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Scale the initial pheromones by an approximation of each edge's betweenness, the fraction of shortest paths
+// that run through it. Bridges between clusters lie on many shortest paths, so they start with more pheromone.
+// The shortest paths (with the edge costs as lengths) are only computed from a sample of samples sources drawn
+// from the colony RNG, and for each source and target a single shortest path is counted. If b_max is the
+// highest count, the pheromone on each edge with count b is multiplied by 1 + b / b_max, so it at most doubles.
+// Each source costs a Dijkstra run plus walking its n shortest paths, O(E log n + n^2) in the worst case
+func (colony *AntColony) applyBetweennessInit() {
+	n := len(colony.constructionGraph.Nodes)
+	counts := colony.newUsageMatrix()
+	sources := colony.rng.Perm(n)
+
+	if colony.betweennessSamples < n {
+		sources = sources[:colony.betweennessSamples]
+	}
+
+	var maxCount uint
+
+	for _, source := range sources {
+		prev := colony.shortestPathTree(uint(source))
+
+		// Walk the path to every target back to the source, counting its edges
+		for target := range prev {
+			for curr := uint(target); prev[curr] != nil; curr = prev[curr].A {
+				edge := *prev[curr]
+				counts[edge.A][colony.column(edge)]++
+				maxCount = max(maxCount, counts[edge.A][colony.column(edge)])
+			}
+		}
+	}
+
+	if maxCount == 0 {
+		return
+	}
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			colony.Pheromones[edge.A][colony.column(edge)] *= 1 + float64(counts[edge.A][colony.column(edge)])/float64(maxCount)
+		}
+	}
+
+	colony.initialPheromones = copyMatrix(colony.Pheromones)
+}
+
+// Dijkstra's algorithm from source, with the edge costs as lengths. Returns the last edge of the shortest path to
+// every component, which is nil for the source and for components that can't be reached
+func (colony *AntColony) shortestPathTree(source uint) []*Edge {
+	n := len(colony.constructionGraph.Nodes)
+	dist := make([]float64, n)
+	prev := make([]*Edge, n)
+
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+
+	dist[source] = 0
+	queue := &distanceQueue{{component: source, dist: 0}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem)
+
+		// A stale entry, since the component was reached by a shorter path in the meantime
+		if item.dist > dist[item.component] {
+			continue
+		}
+
+		for _, edge := range colony.constructionGraph.Edges[item.component] {
+			if edge.A == edge.B {
+				continue
+			}
+
+			if d := item.dist + colony.edgeCost(edge); d < dist[edge.B] {
+				dist[edge.B] = d
+				prev[edge.B] = &edge
+				heap.Push(queue, distanceItem{component: edge.B, dist: d})
+			}
+		}
+	}
+
+	return prev
+}
+
+// A component and its tentative distance in Dijkstra's algorithm
+type distanceItem struct {
+	component uint
+	dist      float64
+}
+
+// A min-heap of components by distance, implementing heap.Interface
+type distanceQueue []distanceItem
+
+func (queue distanceQueue) Len() int           { return len(queue) }
+func (queue distanceQueue) Less(i, j int) bool { return queue[i].dist < queue[j].dist }
+func (queue distanceQueue) Swap(i, j int)      { queue[i], queue[j] = queue[j], queue[i] }
+
+func (queue *distanceQueue) Push(item any) {
+	*queue = append(*queue, item.(distanceItem))
+}
+
+func (queue *distanceQueue) Pop() any {
+	old := *queue
+	item := old[len(old)-1]
+	*queue = old[:len(old)-1]
+
+	return item
+}
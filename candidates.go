@@ -0,0 +1,131 @@
+package antcolony
+
+import "sort"
+
+// Build the nearest-neighbour candidate lists: the k cheapest edges leaving each component (its out-candidates)
+// and the k cheapest edges entering it (its in-candidates), cheapest first. In a directed graph the two differ,
+// since going from i to j may cost something else than coming back. In an undirected graph both directions are
+// the same connection, so each edge {i, j} is ranked by the mean of the costs of (i, j) and (j, i), which smooths
+// over heuristics that aren't exactly symmetric, and the in-candidates are the out-candidates
+func (colony *AntColony) buildCandidateLists() {
+	n := len(colony.constructionGraph.Nodes)
+	outgoing := make([][]Edge, n)
+	incoming := make([][]Edge, n)
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A == edge.B {
+				continue
+			}
+
+			outgoing[edge.A] = append(outgoing[edge.A], edge)
+			incoming[edge.B] = append(incoming[edge.B], edge)
+		}
+	}
+
+	colony.outCandidates = make([][]uint, n)
+	colony.isCandidate = make([][]bool, n)
+
+	for i := range outgoing {
+		colony.outCandidates[i] = colony.nearest(outgoing[i], func(edge Edge) uint { return edge.B })
+		colony.isCandidate[i] = make([]bool, colony.rowLength(i))
+
+		for _, candidate := range colony.outCandidates[i] {
+			colony.isCandidate[i][colony.column(Edge{A: uint(i), B: candidate})] = true
+		}
+	}
+
+	if !colony.constructionGraph.Directed {
+		colony.inCandidates = colony.outCandidates
+		return
+	}
+
+	colony.inCandidates = make([][]uint, n)
+
+	for i := range incoming {
+		colony.inCandidates[i] = colony.nearest(incoming[i], func(edge Edge) uint { return edge.A })
+	}
+}
+
+// The other ends of the (at most) candidateCount cheapest edges, where other picks the end to return
+func (colony *AntColony) nearest(edges []Edge, other func(edge Edge) uint) []uint {
+	costs := make([]float64, len(edges))
+
+	for i, edge := range edges {
+		costs[i] = colony.candidateCost(edge)
+	}
+
+	order := make([]int, len(edges))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	// Ties are broken by the order in which the edges are listed
+	sort.SliceStable(order, func(i, j int) bool { return costs[order[i]] < costs[order[j]] })
+
+	nearest := make([]uint, 0, colony.candidateCount)
+
+	for _, i := range order[:min(colony.candidateCount, len(order))] {
+		nearest = append(nearest, other(edges[i]))
+	}
+
+	return nearest
+}
+
+// The cost an edge is ranked by when building the candidate lists
+func (colony *AntColony) candidateCost(edge Edge) float64 {
+	if colony.constructionGraph.Directed {
+		return colony.edgeCost(edge)
+	}
+
+	return (colony.edgeCost(edge) + colony.edgeCost(Edge{A: edge.B, B: edge.A})) / 2
+}
+
+// Restrict the feasible edges to those leading to the current component's candidates. If none of the candidates
+// is feasible, all the feasible edges are kept, so the candidate lists never leave an ant stuck
+func (colony *AntColony) restrictToCandidates(edges []Edge) []Edge {
+	if colony.candidateCount == 0 {
+		return edges
+	}
+
+	restricted := make([]Edge, 0, len(edges))
+
+	for _, edge := range edges {
+		if colony.isCandidate[edge.A][colony.column(edge)] {
+			restricted = append(restricted, edge)
+		}
+	}
+
+	if len(restricted) == 0 {
+		return edges
+	}
+
+	return restricted
+}
+
+// The out-candidates of a component: the components its cheapest outgoing edges lead to, cheapest first.
+// Empty unless candidate lists were enabled with WithCandidateLists
+func (colony *AntColony) CandidateList(component uint) []uint {
+	if colony.candidateCount == 0 {
+		return []uint{}
+	}
+
+	list := make([]uint, len(colony.outCandidates[component]))
+	copy(list, colony.outCandidates[component])
+
+	return list
+}
+
+// The in-candidates of a component: the components whose edges into it are cheapest, cheapest first. In an
+// undirected graph these are the out-candidates. Empty unless candidate lists were enabled with WithCandidateLists
+func (colony *AntColony) InCandidateList(component uint) []uint {
+	if colony.candidateCount == 0 {
+		return []uint{}
+	}
+
+	list := make([]uint, len(colony.inCandidates[component]))
+	copy(list, colony.inCandidates[component])
+
+	return list
+}
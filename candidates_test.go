@@ -0,0 +1,81 @@
+package antcolony
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCandidateListsFollowTheDirection(t *testing.T) {
+	weights := [][]float64{
+		{0, 1, 5, 9},
+		{8, 0, 2, 6},
+		{3, 7, 0, 4},
+		{2, 9, 6, 0},
+	}
+
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithCandidateLists(2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !colony.constructionGraph.Directed {
+		t.Fatal("the asymmetric instance should have a directed graph")
+	}
+
+	tests := []struct {
+		component uint
+		out       []uint
+		in        []uint
+	}{
+		{0, []uint{1, 2}, []uint{3, 2}},
+		{1, []uint{2, 3}, []uint{0, 2}},
+		{2, []uint{0, 3}, []uint{1, 0}},
+		{3, []uint{0, 2}, []uint{2, 1}},
+	}
+
+	for _, test := range tests {
+		if got := colony.CandidateList(test.component); !slices.Equal(got, test.out) {
+			t.Errorf("out-candidates of %d are %v, expected %v", test.component, got, test.out)
+		}
+
+		if got := colony.InCandidateList(test.component); !slices.Equal(got, test.in) {
+			t.Errorf("in-candidates of %d are %v, expected %v", test.component, got, test.in)
+		}
+	}
+}
+
+func TestCandidateListsOfUndirectedGraphs(t *testing.T) {
+	weights := [][]float64{
+		{0, 4, 1, 3},
+		{4, 0, 2, 5},
+		{1, 2, 0, 6},
+		{3, 5, 6, 0},
+	}
+
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithCandidateLists(2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]uint{{2, 3}, {2, 0}, {0, 1}, {0, 1}}
+
+	for i := range expected {
+		out, in := colony.CandidateList(uint(i)), colony.InCandidateList(uint(i))
+
+		if !slices.Equal(out, expected[i]) || !slices.Equal(in, out) {
+			t.Errorf("candidates of %d are %v out and %v in, expected %v both ways", i, out, in, expected[i])
+		}
+	}
+
+	disabled, err := NewAntColony(NewTSPProblem(weights), WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(disabled.CandidateList(0)) != 0 || len(disabled.InCandidateList(0)) != 0 {
+		t.Error("got candidates without candidate lists enabled")
+	}
+}
@@ -0,0 +1,72 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+)
+
+// The core parameters of the Ant System. Good values differ between problems and instance sizes, so they can be
+// tuned per colony with WithConfig
+type ACOConfig struct {
+	// The pheromone weight: how strongly the ants follow the trails
+	Alpha float64
+	// The heuristic weight: how strongly the ants follow the heuristic (e.g. prefer short edges)
+	Beta float64
+	// The evaporation rate: the fraction of the pheromone that evaporates every iteration
+	Rho float64
+}
+
+// The configuration used unless set otherwise: alpha = 1, beta = 3 and rho = 0.5
+func DefaultACOConfig() ACOConfig {
+	return ACOConfig{Alpha: defaultAlpha, Beta: defaultBeta, Rho: defaultRho}
+}
+
+// Check that the weights are non-negative and finite, and that the evaporation rate is in (0, 1]
+func (config ACOConfig) validate() error {
+	if err := validateWeight("alpha", config.Alpha); err != nil {
+		return err
+	}
+
+	if err := validateWeight("beta", config.Beta); err != nil {
+		return err
+	}
+
+	return validateRho(config.Rho)
+}
+
+func validateWeight(name string, weight float64) error {
+	if math.IsNaN(weight) || math.IsInf(weight, 0) || weight < 0 {
+		return fmt.Errorf("%w: %s must be non-negative and finite, got %f", ErrInvalidParams, name, weight)
+	}
+
+	return nil
+}
+
+func validateRho(rho float64) error {
+	if math.IsNaN(rho) || rho <= 0 || rho > 1 {
+		return fmt.Errorf("%w: rho must be in (0, 1], got %f", ErrInvalidParams, rho)
+	}
+
+	return nil
+}
+
+// Set the colony's alpha, beta and rho. Start from DefaultACOConfig to only change some of them, or use WithAlpha,
+// WithBeta and WithEvaporation
+func WithConfig(config ACOConfig) Option {
+	return func(colony *AntColony) error {
+		if err := config.validate(); err != nil {
+			return err
+		}
+
+		colony.alpha = config.Alpha
+		colony.beta = config.Beta
+		colony.rho = config.Rho
+
+		return nil
+	}
+}
+
+// The colony's current alpha, beta and rho. With WithAdaptiveControl, beta and rho change as the colony runs
+func (colony *AntColony) Config() ACOConfig {
+	return ACOConfig{Alpha: colony.alpha, Beta: colony.beta, Rho: colony.rho}
+}
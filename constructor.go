@@ -0,0 +1,73 @@
+package antcolony
+
+import "fmt"
+
+// A Constructor builds the ants' tours in place of the colony's proportional construction, e.g. with an insertion
+// heuristic or a beam search, while the colony still records, deposits and evaporates as usual. Construct is
+// called with an ant that has already started its tour (and walked the fixed prefix, if there is one); it extends
+// the tour with Ant.Move until it's closed. The tours it completes are recorded like any other, and those it doesn't
+// are treated like tours that ran into a dead end. If Construct fails, the ant's tour is discarded and the error is
+// kept for ConstructionError
+type Constructor interface {
+	Construct(colony *AntColony, ant *Ant) error
+}
+
+// Build an ant's tour with the colony's constructor, falling back to the built-in proportional construction
+func (colony *AntColony) construct(ant *Ant) {
+	if colony.constructor == nil {
+		ant.DoCycle(colony)
+		return
+	}
+
+	ant.begin(colony)
+
+	if err := colony.constructor.Construct(colony, ant); err != nil {
+		// Ants constructing in parallel may fail at the same time
+		colony.constructionMu.Lock()
+		colony.constructionErr = err
+		colony.constructionMu.Unlock()
+		ant.tour = make([]Edge, 0)
+	}
+}
+
+// The last error returned by the colony's constructor since the colony was created or reset, or nil if there was none
+func (colony *AntColony) ConstructionError() error {
+	return colony.constructionErr
+}
+
+// The component the ant is currently at
+func (ant *Ant) Current() uint {
+	return ant.currComponent
+}
+
+// Has the ant closed its tour? Once it has, there are no more moves to make
+func (ant *Ant) Done(colony *AntColony) bool {
+	return ant.closed(colony)
+}
+
+// The components the ant can move to next, and how attractive each of them is, exactly as weighed by the built-in
+// construction: the scores are non-negative, and a component's probability under Roulette selection is its score
+// over their sum. There are no candidates if the ant is stuck in a dead end
+func (ant *Ant) Candidates(colony *AntColony) ([]uint, []float64) {
+	return ant.candidates(colony)
+}
+
+// Move the ant to next, appending the edge to its tour (and applying the local pheromone update, if one is set
+// with WithLocalPheromoneUpdate). Returns an error wrapping ErrInfeasibleTour if the move
+// isn't feasible, in which case the ant doesn't move
+func (ant *Ant) Move(colony *AntColony, next uint) error {
+	for _, edge := range ant.feasibleEdges(colony) {
+		if edge.B == next {
+			colony.step(ant, edge)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: can't move from %d to %d", ErrInfeasibleTour, ant.currComponent, next)
+}
+
+// Abandon the tour constructed so far and start a new one, from a start component drawn as usual
+func (ant *Ant) Restart(colony *AntColony) {
+	ant.ResetSolution(colony)
+	ant.begin(colony)
+}
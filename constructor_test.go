@@ -0,0 +1,56 @@
+package antcolony
+
+import (
+	"reflect"
+	"testing"
+)
+
+// A Constructor that abandons the ant's first start, then always moves to the first candidate
+type restartingConstructor struct{}
+
+func (restartingConstructor) Construct(colony *AntColony, ant *Ant) error {
+	ant.Restart(colony)
+
+	for !ant.Done(colony) {
+		candidates, _ := ant.Candidates(colony)
+
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		if err := ant.Move(colony, candidates[0]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestRestartingConstructorWithWorkers(t *testing.T) {
+	weights := ringWeights(t, 8)
+	var tours [][]Edge
+
+	// Run under -race, the restarts mustn't share the colony's random source
+	for _, workers := range []int{2, 4} {
+		colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(16), WithWorkers(workers),
+			WithConstructor(restartingConstructor{}))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		colony.RunSimulation(5)
+
+		if err := colony.ConstructionError(); err != nil {
+			t.Fatal(err)
+		}
+
+		tour, _ := colony.BestSolution()
+		tours = append(tours, tour)
+	}
+
+	// Each ant restarts from its own random source, so the run doesn't depend on the number of workers
+	if !reflect.DeepEqual(tours[0], tours[1]) {
+		t.Errorf("got best tour %v with 2 workers and %v with 4", tours[0], tours[1])
+	}
+}
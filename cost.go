@@ -0,0 +1,45 @@
+package antcolony
+
+// A TourCost computes the cost of a complete tour. The colony always minimizes it: it decides which
+// tour is the best so far and how much pheromone a tour deposits (1 / cost)
+type TourCost func(tour []Edge) float64
+
+// A problem that knows the cost of its edges. Without it, the colony recovers the cost of an edge as the reciprocal
+// of its heuristic, which is only right if the heuristics are exactly 1 / cost (and not, e.g., blended with other
+// heuristics or offset to avoid dividing by zero). The edge costs are summed into tour costs, which decide the
+// best-so-far tour and the deposits, unless a TourCost is set with WithTourCost; they also rank the edges for
+// candidate lists and long edge penalties
+type CostProblem interface {
+	ACOptimizable
+	// The cost of taking edge
+	Cost(edge Edge) float64
+}
+
+// The standard cost of a tour: the sum of the weights of its edges
+func SumCost(weights [][]float64) TourCost {
+	return func(tour []Edge) float64 {
+		cost := 0.0
+
+		for _, edge := range tour {
+			cost += weights[edge.A][edge.B]
+		}
+
+		return cost
+	}
+}
+
+// The cost of a tour in the bottleneck TSP: the weight of its longest edge.
+// Minimizing it yields tours that avoid long edges, even at the expense of a longer total length
+func BottleneckCost(weights [][]float64) TourCost {
+	return func(tour []Edge) float64 {
+		cost := 0.0
+
+		for _, edge := range tour {
+			if weights[edge.A][edge.B] > cost {
+				cost = weights[edge.A][edge.B]
+			}
+		}
+
+		return cost
+	}
+}
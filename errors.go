@@ -0,0 +1,18 @@
+package antcolony
+
+import "errors"
+
+// The kinds of errors returned by the package. Errors are wrapped with more detail, so check for these with
+// errors.Is, e.g. errors.Is(err, ErrInvalidParams)
+var (
+	// A matrix isn't square, or its size doesn't match the problem or another matrix
+	ErrRaggedMatrix = errors.New("ragged matrix")
+	// The construction graph's nodes aren't numbered 0, ..., n - 1, or its edges don't match its nodes
+	ErrInvalidGraph = errors.New("invalid graph")
+	// The construction graph isn't strongly connected, so no tour can visit every component
+	ErrDisconnectedGraph = errors.New("disconnected graph")
+	// A tour, order or successor form isn't a valid cycle over the components of the problem
+	ErrInfeasibleTour = errors.New("infeasible tour")
+	// A parameter or option is out of range, or can't be combined with another one
+	ErrInvalidParams = errors.New("invalid parameters")
+)
@@ -0,0 +1,85 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+)
+
+// The largest instance BruteForceTSP agrees to solve; 10 cities already mean 9! = 362880 tours
+const maxBruteForceCities = 10
+
+// Find an optimal tour by enumerating all the tours starting at city 0, returning the order in which the cities
+// are visited and the cost of the tour. This takes (n-1)! steps, so it's only feasible for small instances,
+// but it's invaluable as a reference for validating the heuristic on them.
+// If several tours are optimal (e.g. a tour and its reversal on a symmetric instance), the lexicographically
+// smallest order is returned, so the result doesn't depend on floating-point jitter: tours are enumerated in
+// lexicographic order, and a later tour only replaces the best one if it's cheaper by more than a relative 1e-9.
+// Returns an error for instances with more than 10 cities, if the weight matrix isn't square, or if a weight is NaN
+// or infinite, since the pruning compares partial costs against it
+func BruteForceTSP(weights [][]float64) (order []uint, cost float64, err error) {
+	n := len(weights)
+
+	if n > maxBruteForceCities {
+		return nil, 0, fmt.Errorf("%w: brute force is limited to %d cities, got %d", ErrInvalidParams, maxBruteForceCities, n)
+	}
+
+	for i := range weights {
+		if len(weights[i]) != n {
+			return nil, 0, fmt.Errorf("%w: weight matrix row %d has %d entries, expected %d", ErrRaggedMatrix, i, len(weights[i]), n)
+		}
+
+		for j, w := range weights[i] {
+			if math.IsNaN(w) || math.IsInf(w, 0) {
+				return nil, 0, fmt.Errorf("%w: weight (%d, %d) is %v, expected a finite weight", ErrInvalidParams, i, j, w)
+			}
+		}
+	}
+
+	if n == 0 {
+		return []uint{}, 0, nil
+	}
+
+	visited := make([]bool, n)
+	curr := make([]uint, 1, n)
+	cost = math.Inf(1)
+	visited[0] = true
+
+	var search func(partial float64)
+
+	search = func(partial float64) {
+		// No completion of this partial tour can be better than the best tour found so far
+		if !improves(partial, cost, defaultEpsilon) {
+			return
+		}
+
+		last := curr[len(curr)-1]
+
+		if len(curr) == n {
+			total := partial + weights[last][0]
+
+			if improves(total, cost, defaultEpsilon) {
+				cost = total
+				order = make([]uint, n)
+				copy(order, curr)
+			}
+
+			return
+		}
+
+		for next := 1; next < n; next++ {
+			if visited[next] {
+				continue
+			}
+
+			visited[next] = true
+			curr = append(curr, uint(next))
+			search(partial + weights[last][next])
+			curr = curr[:len(curr)-1]
+			visited[next] = false
+		}
+	}
+
+	search(0)
+
+	return order, cost, nil
+}
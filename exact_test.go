@@ -0,0 +1,122 @@
+package antcolony
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestBruteForceTSPRejectsInvalidInstances(t *testing.T) {
+	withWeight := func(w float64) [][]float64 {
+		weights := filledMatrix(4, 1)
+		weights[1][2] = w
+
+		return weights
+	}
+
+	tests := []struct {
+		name    string
+		weights [][]float64
+		err     error
+	}{
+		{"too many cities", filledMatrix(maxBruteForceCities+1, 1), ErrInvalidParams},
+		{"ragged matrix", [][]float64{{0, 1, 2}, {1, 0}, {2, 3, 0}}, ErrRaggedMatrix},
+		{"NaN weight", withWeight(math.NaN()), ErrInvalidParams},
+		{"infinite weight", withWeight(math.Inf(1)), ErrInvalidParams},
+		{"negative infinite weight", withWeight(math.Inf(-1)), ErrInvalidParams},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := BruteForceTSP(test.weights); !errors.Is(err, test.err) {
+				t.Errorf("got error %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestBruteForceTSPBreaksTiesLexicographically(t *testing.T) {
+	const n = 4
+	withEdge := func(i, j int, w float64) [][]float64 {
+		weights := filledMatrix(n, 1)
+		weights[i][j], weights[j][i] = w, w
+
+		return weights
+	}
+
+	tests := []struct {
+		name     string
+		weights  [][]float64
+		expected []uint
+		cost     float64
+	}{
+		// Every tour and its reversal costs 4
+		{"all tours optimal", filledMatrix(n, 1), []uint{0, 1, 2, 3}, 4},
+		// 0 1 3 2 is cheaper by far less than the tolerance, so it doesn't replace the earlier 0 1 2 3
+		{"jitter", withEdge(1, 3, 1-1e-12), []uint{0, 1, 2, 3}, 4},
+		// A real improvement does, and of the tours using (1, 3), 0 1 3 2 comes first
+		{"real improvement", withEdge(1, 3, 0.5), []uint{0, 1, 3, 2}, 3.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, cost, err := BruteForceTSP(test.weights)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(order, test.expected) || cost != test.cost {
+				t.Errorf("got %v costing %v, expected %v costing %v", order, cost, test.expected, test.cost)
+			}
+		})
+	}
+}
+
+func TestGreedyCycleBreaksTiesByIndex(t *testing.T) {
+	const n = 4
+	// The edges are listed from the highest index down, so following the graph's order would pick the wrong one
+	graph := Graph{Nodes: make([]uint, n), Edges: make([][]Edge, n)}
+
+	for i := 0; i < n; i++ {
+		graph.Nodes[i] = uint(i)
+
+		for j := n - 1; j >= 0; j-- {
+			graph.Edges[i] = append(graph.Edges[i], Edge{A: uint(i), B: uint(j)})
+		}
+	}
+
+	preferred := filledMatrix(n, 1)
+	preferred[0][3] = 2
+
+	tests := []struct {
+		name       string
+		heuristics [][]float64
+		start      uint
+		expected   []uint
+	}{
+		{"ties from 0", filledMatrix(n, 1), 0, []uint{0, 1, 2, 3}},
+		{"ties from 2", filledMatrix(n, 1), 2, []uint{2, 0, 1, 3}},
+		{"higher heuristic wins", preferred, 0, []uint{0, 3, 1, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			problem := &matrixProblem{graph: graph, pheromones: filledMatrix(n, 1), heuristics: test.heuristics}
+			colony, err := NewAntColony(problem, WithSeed(1))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ant := colony.newAnt()
+			ant.currComponent = test.start
+			ant.greedyCycle(colony)
+
+			if order := TourOrder(ant.tour); !slices.Equal(order, test.expected) || ant.tour[n-1].B != test.start {
+				t.Errorf("got tour %v, expected the order %v", ant.tour, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// The Rosenbrock function in n dimensions, a classic test of continuous optimizers: its minimum of 0 at
+// (1, ..., 1) lies at the bottom of a long, narrow, curved valley
+type Rosenbrock struct {
+	n int
+}
+
+func (rosenbrock Rosenbrock) Dimensions() int {
+	return rosenbrock.n
+}
+
+func (rosenbrock Rosenbrock) Bounds() ([]float64, []float64) {
+	lower := make([]float64, rosenbrock.n)
+	upper := make([]float64, rosenbrock.n)
+
+	for i := range lower {
+		lower[i] = -5
+		upper[i] = 5
+	}
+
+	return lower, upper
+}
+
+func (rosenbrock Rosenbrock) Objective(x []float64) float64 {
+	value := 0.0
+
+	for i := 0; i+1 < len(x); i++ {
+		a := x[i+1] - x[i]*x[i]
+		b := 1 - x[i]
+		value += 100*a*a + b*b
+	}
+
+	return value
+}
+
+func main() {
+	seed := int64(1337)
+	solver, err := antcolony.NewACORSolver(Rosenbrock{n: 4}, antcolony.ACORParams{Locality: 1e-4, Seed: &seed})
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	solver.Run(20000)
+
+	x, value := solver.Best()
+	fmt.Printf("Best solution: %.4f\n", x)
+	fmt.Printf("Objective: %g\n", value)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// An asymmetric TSP: the cost of going from a to b may differ from the cost of going from b to a.
+// The colony reads heuristics[a][b] and Pheromones[a][b] directionally, so nothing special is needed
+// beyond providing directional matrices
+type AsymmetricTravelingSalesman struct {
+	graph   antcolony.Graph
+	weights [][]float64
+}
+
+func (atsp *AsymmetricTravelingSalesman) ConstructGraph() antcolony.Graph {
+	return atsp.graph
+}
+
+func (atsp *AsymmetricTravelingSalesman) InitPheromones(num_ants uint) [][]float64 {
+	pheromones := make([][]float64, 0)
+
+	for i := 0; i < len(atsp.graph.Nodes); i++ {
+		pheromone := make([]float64, 0)
+
+		for j := 0; j < len(atsp.graph.Nodes); j++ {
+			pheromone = append(pheromone, 1.0)
+		}
+
+		pheromones = append(pheromones, pheromone)
+	}
+
+	return pheromones
+}
+
+// The heuristic of (i, j) depends only on the cost of going from i to j, so the matrix isn't symmetric
+func (atsp *AsymmetricTravelingSalesman) InitHeuristics() [][]float64 {
+	heuristics := make([][]float64, 0)
+
+	for i := 0; i < len(atsp.graph.Nodes); i++ {
+		heuristic := make([]float64, 0)
+
+		for j := 0; j < len(atsp.graph.Nodes); j++ {
+			if i == j {
+				heuristic = append(heuristic, 0)
+				continue
+			}
+
+			heuristic = append(heuristic, 1.0/atsp.weights[i][j])
+		}
+
+		heuristics = append(heuristics, heuristic)
+	}
+
+	return heuristics
+}
+
+func newCompleteGraph(num_nodes uint) antcolony.Graph {
+	nodes := make([]uint, 0)
+	edges := make([][]antcolony.Edge, 0)
+
+	for i := 0; i < int(num_nodes); i++ {
+		nodes = append(nodes, uint(i))
+		curr_edges := make([]antcolony.Edge, 0)
+
+		for j := 0; j < int(num_nodes); j++ {
+			curr_edges = append(curr_edges, antcolony.Edge{A: uint(i), B: uint(j)})
+		}
+
+		edges = append(edges, curr_edges)
+	}
+
+	return antcolony.Graph{Nodes: nodes, Edges: edges, Directed: true}
+}
+
+// Going "clockwise" (i -> i+1) is cheap, while every other move, including going back, is expensive.
+// The best tour is therefore 0 -> 1 -> ... -> n-1 -> 0, and its reverse is among the worst
+func asymmetricWeights(num_nodes uint) [][]float64 {
+	weights := make([][]float64, 0)
+
+	for i := 0; i < int(num_nodes); i++ {
+		curr_weights := make([]float64, 0)
+
+		for j := 0; j < int(num_nodes); j++ {
+			if j == (i+1)%int(num_nodes) {
+				curr_weights = append(curr_weights, 1.0)
+			} else {
+				curr_weights = append(curr_weights, 5.0+rand.Float64())
+			}
+		}
+
+		weights = append(weights, curr_weights)
+	}
+
+	return weights
+}
+
+func main() {
+	const num_nodes = 10
+
+	atsp := AsymmetricTravelingSalesman{graph: newCompleteGraph(num_nodes), weights: asymmetricWeights(num_nodes)}
+
+	antColony, err := antcolony.NewAntColony(&atsp, antcolony.WithAnts(50))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	antColony.RunSimulation(50)
+
+	tour, cost := antColony.BestSolution()
+
+	// Validation treats edges as directed, so a reversed tour wouldn't be mistaken for this one
+	if err := antcolony.ValidateTour(tour, num_nodes); err != nil {
+		fmt.Println("invalid tour:", err)
+		return
+	}
+
+	for _, edge := range tour {
+		fmt.Printf("(%d, %d)\n", edge.A, edge.B)
+	}
+
+	fmt.Printf("Cost: %f\n", cost)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// A small instance where the shortest tour (0, 1, 2, 4, 3) has length 26 but uses an edge of weight 8,
+// while the bottleneck-optimal tour (0, 2, 1, 4, 3) never uses an edge longer than 7 at a total length of 27
+var weights = [][]float64{
+	{0, 8, 7, 6, 5},
+	{8, 0, 5, 9, 5},
+	{7, 5, 0, 8, 3},
+	{6, 9, 8, 0, 4},
+	{5, 5, 3, 4, 0},
+}
+
+func main() {
+	sumOrder, sumCost, err := antcolony.SolveTSP(weights, antcolony.WithSeed(1337))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Min-sum tour: %v (length %.0f)\n", sumOrder, sumCost)
+
+	// The only change needed for the bottleneck TSP is the cost function
+	maxOrder, maxCost, err := antcolony.SolveTSP(weights, antcolony.WithSeed(1337), antcolony.WithTourCost(antcolony.BottleneckCost(weights)))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Min-max tour: %v (longest edge %.0f)\n", maxOrder, maxCost)
+}
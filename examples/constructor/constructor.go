@@ -0,0 +1,205 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// A sparse TSP: the points lie around a circle, and each point is only connected to the few points nearest to
+// it along the circle. Going around the circle is always possible, but a tour that skips ahead carelessly can
+// strand the points it skipped
+type SparseTravelingSalesman struct {
+	graph   antcolony.Graph
+	weights [][]float64
+}
+
+func (tsp *SparseTravelingSalesman) ConstructGraph() antcolony.Graph {
+	return tsp.graph
+}
+
+func (tsp *SparseTravelingSalesman) InitPheromones(num_ants uint) [][]float64 {
+	pheromones := make([][]float64, 0)
+
+	for i := 0; i < len(tsp.graph.Nodes); i++ {
+		pheromone := make([]float64, 0)
+
+		for j := 0; j < len(tsp.graph.Nodes); j++ {
+			pheromone = append(pheromone, 1.0)
+		}
+
+		pheromones = append(pheromones, pheromone)
+	}
+
+	return pheromones
+}
+
+func (tsp *SparseTravelingSalesman) InitHeuristics() [][]float64 {
+	heuristics := make([][]float64, 0)
+
+	for i := 0; i < len(tsp.graph.Nodes); i++ {
+		heuristic := make([]float64, 0)
+
+		for j := 0; j < len(tsp.graph.Nodes); j++ {
+			if i == j {
+				heuristic = append(heuristic, 0)
+				continue
+			}
+
+			heuristic = append(heuristic, 1.0/tsp.weights[i][j])
+		}
+
+		heuristics = append(heuristics, heuristic)
+	}
+
+	return heuristics
+}
+
+// num_points points jittered around the unit circle, each connected to the reach points on either side of it
+func newSparseInstance(num_points int, reach int, rng *rand.Rand) *SparseTravelingSalesman {
+	xs := make([]float64, num_points)
+	ys := make([]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		angle := 2 * math.Pi * (float64(i) + rng.Float64()*0.8) / float64(num_points)
+		radius := 1 + rng.Float64()*0.3
+		xs[i] = radius * math.Cos(angle)
+		ys[i] = radius * math.Sin(angle)
+	}
+
+	nodes := make([]uint, 0)
+	edges := make([][]antcolony.Edge, 0)
+	weights := make([][]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		nodes = append(nodes, uint(i))
+		curr_edges := make([]antcolony.Edge, 0)
+		weights[i] = make([]float64, num_points)
+
+		for j := 0; j < num_points; j++ {
+			weights[i][j] = math.Hypot(xs[i]-xs[j], ys[i]-ys[j])
+		}
+
+		for offset := -reach; offset <= reach; offset++ {
+			if offset != 0 {
+				j := (i + offset + num_points) % num_points
+				curr_edges = append(curr_edges, antcolony.Edge{A: uint(i), B: uint(j)})
+			}
+		}
+
+		edges = append(edges, curr_edges)
+	}
+
+	return &SparseTravelingSalesman{graph: antcolony.Graph{Nodes: nodes, Edges: edges}, weights: weights}
+}
+
+// Greedy construction with restarts: at every step the ant takes the most attractive candidate with probability
+// Greediness, and otherwise draws one in proportion to the scores. When the ant is stuck in a dead end, it starts
+// over from a new start, up to MaxRestarts times
+type GreedyRestartConstructor struct {
+	Greediness  float64
+	MaxRestarts int
+	rng         *rand.Rand
+	// How many times an ant had to start over
+	restarts int
+}
+
+var errGaveUp = errors.New("every restart ran into a dead end")
+
+func (constructor *GreedyRestartConstructor) Construct(colony *antcolony.AntColony, ant *antcolony.Ant) error {
+	for attempt := 0; attempt <= constructor.MaxRestarts; attempt++ {
+		if attempt > 0 {
+			ant.Restart(colony)
+			constructor.restarts++
+		}
+
+		for !ant.Done(colony) {
+			candidates, scores := ant.Candidates(colony)
+
+			if len(candidates) == 0 {
+				break
+			}
+
+			if err := ant.Move(colony, constructor.choose(candidates, scores)); err != nil {
+				return err
+			}
+		}
+
+		if ant.Done(colony) {
+			return nil
+		}
+	}
+
+	return errGaveUp
+}
+
+func (constructor *GreedyRestartConstructor) choose(candidates []uint, scores []float64) uint {
+	if constructor.rng.Float64() < constructor.Greediness {
+		best := 0
+
+		for i := range scores {
+			if scores[i] > scores[best] {
+				best = i
+			}
+		}
+
+		return candidates[best]
+	}
+
+	total := 0.0
+
+	for _, score := range scores {
+		total += score
+	}
+
+	r := constructor.rng.Float64() * total
+
+	for i, score := range scores {
+		r -= score
+
+		if r <= 0 {
+			return candidates[i]
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(7))
+	tsp := newSparseInstance(30, 3, rng)
+	constructor := &GreedyRestartConstructor{Greediness: 0.8, MaxRestarts: 5, rng: rng}
+
+	builtIn, err := antcolony.NewAntColony(tsp, antcolony.WithAnts(20), antcolony.WithSeed(7))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	custom, err := antcolony.NewAntColony(tsp, antcolony.WithAnts(20), antcolony.WithSeed(7), antcolony.WithConstructor(constructor))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	builtIn.RunSimulation(50)
+	custom.RunSimulation(50)
+
+	_, builtInCost := builtIn.BestSolution()
+	tour, customCost := custom.BestSolution()
+
+	fmt.Printf("Built-in construction: %f\n", builtInCost)
+	fmt.Printf("Greedy with restarts:  %f (%d restarts)\n", customCost, constructor.restarts)
+
+	if err := antcolony.ValidateTour(tour, len(tsp.graph.Nodes)); err != nil {
+		fmt.Println("invalid tour:", err)
+	}
+
+	if err := custom.ConstructionError(); err != nil {
+		fmt.Println("Some ants gave up:", err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	antcolony "vaktibabat/ant_colony"
+)
+
+const (
+	// Component 0 is the depot, the rest are customers
+	depot        = 0
+	numCustomers = 12
+	// The depot may be visited this many times, so the tour consists of up to this many routes
+	numRoutes = 3
+	// Each vehicle serves at most this many customers; every customer above it is penalized
+	maxStops       = 5
+	overloadFactor = 1.0
+)
+
+// Split a tour starting at the depot into routes, each leaving the depot and returning to it
+func routes(tour []antcolony.Edge) [][]uint {
+	res := make([][]uint, 0)
+	curr := make([]uint, 0)
+
+	for _, edge := range tour {
+		if edge.B == depot {
+			res = append(res, curr)
+			curr = make([]uint, 0)
+		} else {
+			curr = append(curr, edge.B)
+		}
+	}
+
+	return res
+}
+
+// The total distance travelled, plus a penalty for every customer served beyond a vehicle's capacity
+func routingCost(weights [][]float64) antcolony.TourCost {
+	distance := antcolony.SumCost(weights)
+
+	return func(tour []antcolony.Edge) float64 {
+		cost := distance(tour)
+
+		for _, route := range routes(tour) {
+			if len(route) > maxStops {
+				cost += overloadFactor * float64(len(route)-maxStops)
+			}
+		}
+
+		return cost
+	}
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(1337))
+	num_nodes := numCustomers + 1
+	xs := make([]float64, num_nodes)
+	ys := make([]float64, num_nodes)
+
+	// The depot is in the middle, and the customers are scattered around it
+	xs[depot], ys[depot] = 0.5, 0.5
+
+	for i := 1; i < num_nodes; i++ {
+		xs[i] = rng.Float64()
+		ys[i] = rng.Float64()
+	}
+
+	weights := make([][]float64, num_nodes)
+
+	for i := range weights {
+		weights[i] = make([]float64, num_nodes)
+
+		for j := range weights[i] {
+			weights[i][j] = math.Hypot(xs[i]-xs[j], ys[i]-ys[j])
+		}
+	}
+
+	// All the ants start at the depot, which they may return to between routes
+	antColony, err := antcolony.NewAntColony(antcolony.NewTSPProblem(weights), antcolony.WithAnts(30),
+		antcolony.WithSeed(1337),
+		antcolony.WithFixedPrefix([]uint{depot}),
+		antcolony.WithVisitCapacity(map[uint]int{depot: numRoutes}),
+		antcolony.WithTourCost(routingCost(weights)))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	antColony.RunSimulation(200)
+
+	tour, cost := antColony.BestSolution()
+
+	for i, route := range routes(tour) {
+		fmt.Printf("Route %d: %v\n", i+1, route)
+	}
+
+	fmt.Printf("Cost: %f\n", cost)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strings"
+	antcolony "vaktibabat/ant_colony"
+)
+
+const (
+	numPoints = 100
+	numAnts   = 30
+	numIters  = 300
+	barWidth  = 40
+)
+
+// The distance matrix between num_points random points in the unit square
+func randomEuclideanWeights(num_points int, rng *rand.Rand) [][]float64 {
+	xs := make([]float64, num_points)
+	ys := make([]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		xs[i] = rng.Float64()
+		ys[i] = rng.Float64()
+	}
+
+	weights := make([][]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		weights[i] = make([]float64, num_points)
+
+		for j := 0; j < num_points; j++ {
+			weights[i][j] = math.Hypot(xs[i]-xs[j], ys[i]-ys[j])
+		}
+	}
+
+	return weights
+}
+
+// Redraw the progress bar in place
+func render(stats antcolony.IterationStats) {
+	done := barWidth * stats.Iteration / numIters
+	bar := strings.Repeat("#", done) + strings.Repeat("-", barWidth-done)
+
+	fmt.Printf("\r[%s] %d/%d  best: %f", bar, stats.Iteration, numIters, stats.BestSoFar)
+}
+
+func main() {
+	weights := randomEuclideanWeights(numPoints, rand.New(rand.NewSource(42)))
+	// The callback runs on the solver's goroutine, so it only hands the statistics over to the renderer.
+	// The channel is buffered, and the renderer drains it until it's closed, so the solver never blocks for long
+	progress := make(chan antcolony.IterationStats, 16)
+
+	antColony, err := antcolony.NewAntColony(antcolony.NewTSPProblem(weights), antcolony.WithAnts(numAnts),
+		antcolony.WithSeed(42),
+		antcolony.WithIterationCallback(func(stats antcolony.IterationStats) { progress <- stats }))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Stop between iterations when the user hits Ctrl-C
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	go func() {
+		defer close(progress)
+
+		for i := 0; i < numIters; i++ {
+			select {
+			case <-interrupt:
+				return
+			default:
+			}
+
+			// Running one iteration at a time gives us a chance to stop in between
+			antColony.RunSimulation(1)
+		}
+	}()
+
+	for stats := range progress {
+		render(stats)
+	}
+
+	fmt.Println()
+
+	_, cost := antColony.BestSolution()
+
+	if antColony.IterationsRun() < numIters {
+		fmt.Printf("Interrupted after %d iterations\n", antColony.IterationsRun())
+	}
+
+	fmt.Printf("Best tour cost: %f\n", cost)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	antcolony "vaktibabat/ant_colony"
+)
+
+const (
+	// Component 0 is the depot, the rest are customers
+	depot = 0
+	// The depot may be visited this many times, so the tour consists of up to this many routes
+	numVehicles = 2
+	// The fixed cost of dispatching a vehicle, so serving everyone with a single vehicle is preferred
+	vehicleCost = 10.0
+)
+
+// The customers lie on a line, at distance i from the depot. Travelling one unit of distance takes one unit of time
+var positions = []float64{0, 1, 2, 3, 4, 5}
+
+// The time window of each customer: a vehicle that arrives early waits until the window opens, and one that
+// would arrive after it closes can't serve the customer. With a single vehicle, the windows force a single
+// feasible order: 1, 3, 2, 4, 5
+var windows = map[uint][2]float64{
+	1: {0, 1.5},
+	2: {3.5, 4.5},
+	3: {2, 3.5},
+	4: {5, 6.5},
+	5: {6.5, 7.5},
+}
+
+// A routing problem with time windows. Every vehicle leaves the depot at time 0
+type timeWindowProblem struct {
+	*antcolony.TSPProblem
+	weights [][]float64
+}
+
+// The time at which the vehicle that takes edge is done at edge.B, given the tour so far
+func (problem *timeWindowProblem) arrival(tour []antcolony.Edge, edge antcolony.Edge) float64 {
+	t := 0.0
+
+	// Copy the tour, so appending the edge doesn't write into the ant's own
+	for _, e := range append(append([]antcolony.Edge{}, tour...), edge) {
+		// A new vehicle leaves the depot
+		if e.A == depot {
+			t = 0
+		}
+
+		t += problem.weights[e.A][e.B]
+
+		if window, ok := windows[e.B]; ok {
+			t = math.Max(t, window[0])
+		}
+	}
+
+	return t
+}
+
+// An edge is feasible if the vehicle reaches its end before the window there closes. The depot has no window
+func (problem *timeWindowProblem) Feasible(tour []antcolony.Edge, edge antcolony.Edge) bool {
+	window, ok := windows[edge.B]
+
+	if !ok {
+		return true
+	}
+
+	return problem.arrival(tour, edge) <= window[1]
+}
+
+// Split a tour starting at the depot into routes, each leaving the depot and returning to it
+func routes(tour []antcolony.Edge) [][]uint {
+	res := make([][]uint, 0)
+	curr := make([]uint, 0)
+
+	for _, edge := range tour {
+		if edge.B == depot {
+			res = append(res, curr)
+			curr = make([]uint, 0)
+		} else {
+			curr = append(curr, edge.B)
+		}
+	}
+
+	return res
+}
+
+// The total distance travelled, plus the fixed cost of every vehicle used
+func routingCost(weights [][]float64) antcolony.TourCost {
+	distance := antcolony.SumCost(weights)
+
+	return func(tour []antcolony.Edge) float64 {
+		return distance(tour) + vehicleCost*float64(len(routes(tour)))
+	}
+}
+
+func main() {
+	num_nodes := len(positions)
+	weights := make([][]float64, num_nodes)
+
+	for i := range weights {
+		weights[i] = make([]float64, num_nodes)
+
+		for j := range weights[i] {
+			weights[i][j] = math.Abs(positions[i] - positions[j])
+		}
+	}
+
+	problem := &timeWindowProblem{TSPProblem: antcolony.NewTSPProblem(weights), weights: weights}
+
+	// All the ants start at the depot, which they may return to between routes
+	antColony, err := antcolony.NewAntColony(problem, antcolony.WithAnts(30),
+		antcolony.WithSeed(1337),
+		antcolony.WithFixedPrefix([]uint{depot}),
+		antcolony.WithVisitCapacity(map[uint]int{depot: numVehicles}),
+		antcolony.WithTourCost(routingCost(weights)))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	antColony.RunSimulation(200)
+
+	tour, cost := antColony.BestSolution()
+
+	if len(tour) == 0 {
+		fmt.Println("No feasible schedule found")
+		return
+	}
+
+	for i, route := range routes(tour) {
+		fmt.Printf("Vehicle %d: %v\n", i+1, route)
+	}
+
+	fmt.Printf("Cost: %f\n", cost)
+}
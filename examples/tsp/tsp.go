@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"math"
 	"math/rand"
@@ -14,13 +15,17 @@ import (
 type TravelingSalesman struct {
 	graph   antcolony.Graph
 	weights [][]float64
+	// Custom heuristics read from a file; if nil, the heuristics are derived from the weights
+	heuristics [][]float64
+	// Where the greedy solution starts is random, so it's drawn from a seeded source to keep runs reproducible
+	rng *rand.Rand
 }
 
 // Used when computing the pheromones for ACO: the pheromones are set to the repricorial of the length of a
 // hamilitonian cycle found with a greedy nearest-neighbour search
 func (tsp TravelingSalesman) greedySolution() float64 {
 	tour := make([]antcolony.Edge, 0)
-	initComponent := uint(rand.Intn(len(tsp.graph.Nodes)))
+	initComponent := uint(tsp.rng.Intn(len(tsp.graph.Nodes)))
 	currComponent := initComponent
 	memory := make(map[uint]bool)
 	tourCost := 0.0
@@ -38,7 +43,6 @@ func (tsp TravelingSalesman) greedySolution() float64 {
 					bestEdge = edge
 					bestWeight = tsp.weights[edge.A][edge.B]
 				}
-				bestEdge = edge
 			}
 		}
 
@@ -62,12 +66,14 @@ func (tsp *TravelingSalesman) ConstructGraph() antcolony.Graph {
 
 func (tsp *TravelingSalesman) InitPheromones(num_ants uint) [][]float64 {
 	pheromones := make([][]float64, 0)
+	// The greedy solution is randomized, so we compute it once for all the edges
+	tau0 := float64(num_ants) / tsp.greedySolution()
 
 	for i := 0; i < len(tsp.graph.Nodes); i++ {
 		pheromone := make([]float64, 0)
 
 		for j := 0; j < len(tsp.graph.Nodes); j++ {
-			pheromone = append(pheromone, float64(num_ants)/tsp.greedySolution())
+			pheromone = append(pheromone, tau0)
 		}
 
 		pheromones = append(pheromones, pheromone)
@@ -76,13 +82,29 @@ func (tsp *TravelingSalesman) InitPheromones(num_ants uint) [][]float64 {
 	return pheromones
 }
 
+// The cost of an edge is its weight, even when the heuristics come from a file and aren't 1 / weight, so the best
+// tour and the deposits are always judged by the tour's length
+func (tsp *TravelingSalesman) Cost(edge antcolony.Edge) float64 {
+	return tsp.weights[edge.A][edge.B]
+}
+
 func (tsp *TravelingSalesman) InitHeuristics() [][]float64 {
+	if tsp.heuristics != nil {
+		return tsp.heuristics
+	}
+
 	heuristics := make([][]float64, 0)
 
 	for i := 0; i < len(tsp.graph.Nodes); i++ {
 		heuristic := make([]float64, 0)
 
 		for j := 0; j < len(tsp.graph.Nodes); j++ {
+			// There is no meaningful heuristic for a self-loop
+			if i == j {
+				heuristic = append(heuristic, 0)
+				continue
+			}
+
 			heuristic = append(heuristic, 1.0/(tsp.weights[i][j]+1e-8))
 		}
 
@@ -92,24 +114,6 @@ func (tsp *TravelingSalesman) InitHeuristics() [][]float64 {
 	return heuristics
 }
 
-func newCompleteGraph(num_nodes uint) antcolony.Graph {
-	nodes := make([]uint, 0)
-	edges := make([][]antcolony.Edge, 0)
-
-	for i := 0; i < int(num_nodes); i++ {
-		nodes = append(nodes, uint(i))
-		curr_edges := make([]antcolony.Edge, 0)
-
-		for j := 0; j < int(num_nodes); j++ {
-			curr_edges = append(curr_edges, antcolony.Edge{A: uint(i), B: uint(j)})
-		}
-
-		edges = append(edges, curr_edges)
-	}
-
-	return antcolony.Graph{Nodes: nodes, Edges: edges}
-}
-
 func randomWeights(num_nodes uint) [][]float64 {
 	weights := make([][]float64, 0)
 
@@ -134,39 +138,123 @@ func randomWeights(num_nodes uint) [][]float64 {
 	return weights
 }
 
-func weightsFromFile(path string) [][]float64 {
-	weights := make([][]float64, 0)
-	file, _ := os.Open(path)
+// Read a square matrix of finite non-negative numbers from a file, one row per line with the entries separated by
+// whitespace. Blank lines are skipped, and a file without any rows is rejected
+func matrixFromFile(path string) ([][]float64, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
 
 	defer file.Close()
 
+	matrix := make([][]float64, 0)
 	scanner := bufio.NewScanner(file)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		curr_weights := make([]float64, 0)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
 
-		for _, weight := range strings.Split(line, " ") {
-			weight_parsed, _ := strconv.ParseFloat(weight, 64)
-			curr_weights = append(curr_weights, weight_parsed)
+		if len(fields) == 0 {
+			continue
 		}
 
-		weights = append(weights, curr_weights)
+		row := make([]float64, 0, len(fields))
+
+		for _, field := range fields {
+			value, err := strconv.ParseFloat(field, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+
+			if value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+				return nil, fmt.Errorf("%s:%d: %v must be finite and non-negative", path, line, value)
+			}
+
+			row = append(row, value)
+		}
+
+		matrix = append(matrix, row)
 	}
 
-	return weights
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(matrix) == 0 {
+		return nil, fmt.Errorf("%s: no rows", path)
+	}
+
+	for i, row := range matrix {
+		if len(row) != len(matrix) {
+			return nil, fmt.Errorf("%s: row %d has %d entries, expected %d", path, i, len(row), len(matrix))
+		}
+	}
+
+	return matrix, nil
+}
+
+// Read the weight matrix of a TSP from a file, as described in matrixFromFile
+func weightsFromFile(path string) ([][]float64, error) {
+	return matrixFromFile(path)
+}
+
+// Read a precomputed heuristic matrix from a file, as described in matrixFromFile, e.g. learned attractiveness
+// values instead of 1 / weight. The diagonal is ignored, since self-loops are never taken
+func heuristicsFromFile(path string) ([][]float64, error) {
+	return matrixFromFile(path)
 }
 
 func main() {
-	graph := newCompleteGraph(20)
-	weights := weightsFromFile("./dist_mat")
+	seedFlag := flag.Int64("seed", 0, "the random seed (derived from the instance by default)")
+	heuristicsPath := flag.String("heuristics", "", "a file with precomputed heuristics (1 / weight by default)")
+	flag.Parse()
+
+	weights, err := weightsFromFile("./dist_mat")
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	graph := antcolony.NewCompleteGraph(uint(len(weights)))
+
+	// Unless a seed is given, the same instance file gives the same result for everyone
+	seed := antcolony.SeedFromInstance(weights)
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seed = *seedFlag
+		}
+	})
+
+	tsp := TravelingSalesman{graph: graph, weights: weights, rng: rand.New(rand.NewSource(seed))}
+
+	if *heuristicsPath != "" {
+		tsp.heuristics, err = heuristicsFromFile(*heuristicsPath)
 
-	tsp := TravelingSalesman{graph: graph, weights: weights}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		if len(tsp.heuristics) != len(weights) {
+			fmt.Printf("%d x %d heuristics don't match %d cities\n", len(tsp.heuristics), len(tsp.heuristics), len(weights))
+			return
+		}
+	}
+
+	antColony, err := antcolony.NewAntColony(&tsp, antcolony.WithAnts(200), antcolony.WithSeed(seed))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
-	antColony := antcolony.NewAntColony(&tsp, 200)
 	antColony.RunSimulation(100)
 
-	cycle := antColony.GetSolution()
+	cycle, _ := antColony.BestSolution()
 
 	for _, edge := range cycle {
 		fmt.Printf("(%d, %d)\n", edge.A, edge.B)
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// With heuristics from a file, the tours must still be judged by their weights rather than by 1 / heuristic
+func TestCustomHeuristicsKeepTheWeightsAsCosts(t *testing.T) {
+	weights := randomWeights(6)
+	heuristics := make([][]float64, len(weights))
+
+	for i := range heuristics {
+		heuristics[i] = make([]float64, len(weights))
+
+		for j := range heuristics[i] {
+			heuristics[i][j] = 1
+		}
+	}
+
+	tsp := TravelingSalesman{graph: antcolony.NewCompleteGraph(6), weights: weights, heuristics: heuristics, rng: rand.New(rand.NewSource(1))}
+	colony, err := antcolony.NewAntColony(&tsp, antcolony.WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(5)
+	tour, cost := colony.BestSolution()
+
+	if expected := antcolony.SumCost(weights)(tour); cost != expected {
+		t.Errorf("got cost %v, expected the tour's length %v", cost, expected)
+	}
+}
+
+func TestMatrixFromFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected [][]float64
+	}{
+		{"square", "0 1.5 2\n1.5 0 3\n2 3 0\n", [][]float64{{0, 1.5, 2}, {1.5, 0, 3}, {2, 3, 0}}},
+		{"blank lines and extra whitespace", "\n  0\t4 \n\n4   0\n\n", [][]float64{{0, 4}, {4, 0}}},
+		{"no trailing newline", "7", [][]float64{{7}}},
+		{"ragged", "0 1 2\n1 0\n2 3 0\n", nil},
+		{"too many columns", "0 1 2\n1 0 3\n", nil},
+		{"malformed entry", "0 1\n1 x\n", nil},
+		{"negative entry", "0 -1\n1 0\n", nil},
+		{"NaN entry", "0 NaN\n1 0\n", nil},
+		{"infinite entry", "0 1\n+Inf 0\n", nil},
+		{"empty", "", nil},
+		{"only blank lines", "\n \n\t\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "matrix")
+
+			if err := os.WriteFile(path, []byte(test.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, read := range []func(string) ([][]float64, error){matrixFromFile, weightsFromFile, heuristicsFromFile} {
+				matrix, err := read(path)
+
+				if test.expected == nil {
+					if err == nil {
+						t.Errorf("got %v, expected an error", matrix)
+					}
+				} else if err != nil || !reflect.DeepEqual(matrix, test.expected) {
+					t.Errorf("got %v (%v), expected %v", matrix, err, test.expected)
+				}
+			}
+		})
+	}
+
+	if _, err := matrixFromFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("reading a missing file succeeded")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	antcolony "vaktibabat/ant_colony"
+)
+
+// The distance matrix between num_points random points in the unit square
+func randomEuclideanWeights(num_points int) [][]float64 {
+	xs := make([]float64, num_points)
+	ys := make([]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		xs[i] = rand.Float64()
+		ys[i] = rand.Float64()
+	}
+
+	weights := make([][]float64, num_points)
+
+	for i := 0; i < num_points; i++ {
+		weights[i] = make([]float64, num_points)
+
+		for j := 0; j < num_points; j++ {
+			weights[i][j] = math.Hypot(xs[i]-xs[j], ys[i]-ys[j])
+		}
+	}
+
+	return weights
+}
+
+func main() {
+	weights := randomEuclideanWeights(50)
+	cost := antcolony.SumCost(weights)
+
+	// Run a short ACO simulation
+	antColony, err := antcolony.NewAntColony(antcolony.NewTSPProblem(weights), antcolony.WithAnts(20))
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	antColony.RunSimulation(20)
+
+	// Take the best tour found by the colony...
+	tour, _ := antColony.BestSolution()
+	fmt.Printf("ACO tour cost: %f\n", cost(tour))
+
+	// ...and polish it once with 2-opt. The weights are symmetric, so 2-opt applies
+	polished := antcolony.TwoOpt(tour, weights)
+	fmt.Printf("After 2-opt:   %f\n", cost(polished))
+}
@@ -0,0 +1,58 @@
+package antcolony
+
+import "fmt"
+
+// Generate a rows x cols grid instance: the complete graph over the grid's points, numbered row by row, and the
+// Manhattan distances between them. Since every tour step costs at least 1, the optimal tour length is known:
+// rows * cols if that is even (e.g. 16 for a 4 x 4 grid, by snaking along the rows and returning along the first
+// column), and rows * cols + 1 if it is odd, since the grid's checkerboard coloring forces one diagonal step. A single
+// row or column is a path, so its optimal tour goes to the end and back, with length 2 * (rows * cols - 1).
+// Returns an error unless rows and cols are both at least 1
+func GridGraph(rows, cols int) (Graph, [][]float64, error) {
+	if rows < 1 || cols < 1 {
+		return Graph{}, nil, fmt.Errorf("%w: grid must have at least one row and column, got %d x %d", ErrInvalidParams, rows, cols)
+	}
+
+	n := rows * cols
+	weights := make([][]float64, n)
+
+	for i := 0; i < n; i++ {
+		weights[i] = make([]float64, n)
+
+		for j := 0; j < n; j++ {
+			weights[i][j] = float64(abs(i/cols-j/cols) + abs(i%cols-j%cols))
+		}
+	}
+
+	return NewCompleteGraph(uint(n)), weights, nil
+}
+
+// Generate a ring instance of n points: the complete graph over the points, and the number of unit steps between
+// them around the ring (so neighbours are 1 apart). The optimal tour goes around the ring, in either direction, with
+// length n. Returns an error if n is less than 2, since a ring needs at least two points
+func RingGraph(n int) (Graph, [][]float64, error) {
+	if n < 2 {
+		return Graph{}, nil, fmt.Errorf("%w: ring must have at least 2 points, got %d", ErrInvalidParams, n)
+	}
+
+	weights := make([][]float64, n)
+
+	for i := 0; i < n; i++ {
+		weights[i] = make([]float64, n)
+
+		for j := 0; j < n; j++ {
+			steps := abs(i - j)
+			weights[i][j] = float64(min(steps, n-steps))
+		}
+	}
+
+	return NewCompleteGraph(uint(n)), weights, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,90 @@
+package antcolony
+
+import (
+	"errors"
+	"testing"
+)
+
+// The weights of a ring of n points, failing the test if they can't be generated
+func ringWeights(t *testing.T, n int) [][]float64 {
+	t.Helper()
+	_, weights, err := RingGraph(n)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return weights
+}
+
+func TestGeneratorsKnownOptima(t *testing.T) {
+	grid := func(rows, cols int) func() ([][]float64, error) {
+		return func() ([][]float64, error) {
+			_, weights, err := GridGraph(rows, cols)
+			return weights, err
+		}
+	}
+	ring := func(n int) func() ([][]float64, error) {
+		return func() ([][]float64, error) {
+			_, weights, err := RingGraph(n)
+			return weights, err
+		}
+	}
+
+	tests := []struct {
+		name     string
+		generate func() ([][]float64, error)
+		optimum  float64
+	}{
+		{"2 x 2 grid", grid(2, 2), 4},
+		{"2 x 3 grid", grid(2, 3), 6},
+		{"3 x 3 grid", grid(3, 3), 10},
+		{"single row", grid(1, 4), 6},
+		{"single column", grid(5, 1), 8},
+		{"single point", grid(1, 1), 0},
+		{"ring of 2", ring(2), 2},
+		{"ring of 3", ring(3), 3},
+		{"ring of 8", ring(8), 8},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			weights, err := test.generate()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, optimum, err := BruteForceTSP(weights)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if optimum != test.optimum {
+				t.Errorf("got optimum %v, expected %v", optimum, test.optimum)
+			}
+		})
+	}
+}
+
+func TestGeneratorsRejectInvalidSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		generate func() error
+	}{
+		{"negative rows", func() error { _, _, err := GridGraph(-1, 2); return err }},
+		{"zero columns", func() error { _, _, err := GridGraph(3, 0); return err }},
+		{"empty ring", func() error { _, _, err := RingGraph(0); return err }},
+		{"ring of 1", func() error { _, _, err := RingGraph(1); return err }},
+		{"negative ring", func() error { _, _, err := RingGraph(-4); return err }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.generate(); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("got error %v, expected %v", err, ErrInvalidParams)
+			}
+		})
+	}
+}
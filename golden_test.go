@@ -0,0 +1,116 @@
+package antcolony
+
+import (
+	"encoding/json"
+	"flag"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Regenerate the golden files instead of checking against them, after an intended change to the algorithm
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const (
+	goldenCities = 12
+	goldenAnts   = 10
+	goldenIters  = 30
+	goldenSeed   = 1337
+)
+
+// The best tour found by a seeded run of a configuration, as stored in its golden file
+type goldenResult struct {
+	Order []uint  `json:"order"`
+	Cost  float64 `json:"cost"`
+}
+
+// The configurations whose results are pinned. Each one exercises a different part of the algorithm
+var goldenConfigs = map[string][]Option{
+	"ant-cycle":  {},
+	"elitist":    {WithPheromoneStrategy(ElitistStrategy{Weight: goldenAnts})},
+	"mmas":       {WithPheromoneStrategy(MaxMinStrategy{})},
+	"as-rank":    {WithPheromoneStrategy(RankStrategy{})},
+	"p-aco":      {WithPheromoneStrategy(&PopulationStrategy{})},
+	"bwas":       {WithPheromoneStrategy(BestWorstStrategy{MutationRate: 0.1, MutationStrength: 0.5})},
+	"tournament": {WithSelection(Tournament)},
+	"rank":       {WithSelection(RankProportional)},
+	"log-space":  {WithLogSpaceScores(true)},
+	"greedy":     {WithGreedySeeds(true)},
+	"acs":        {WithPheromoneStrategy(ACSStrategy{}), WithLocalPheromoneUpdate(0.1), WithPseudoRandomProportional(0.9)},
+	"hyper-cube": {WithHyperCube(true)},
+}
+
+// A random Euclidean instance, generated from a fixed seed so it's the same on every run
+func goldenInstance() [][]float64 {
+	rng := rand.New(rand.NewSource(goldenSeed))
+	xs := make([]float64, goldenCities)
+	ys := make([]float64, goldenCities)
+
+	for i := range xs {
+		xs[i] = rng.Float64()
+		ys[i] = rng.Float64()
+	}
+
+	weights := make([][]float64, goldenCities)
+
+	for i := range weights {
+		weights[i] = make([]float64, goldenCities)
+
+		for j := range weights[i] {
+			weights[i][j] = math.Hypot(xs[i]-xs[j], ys[i]-ys[j])
+		}
+	}
+
+	return weights
+}
+
+// Check that seeded runs still find exactly the tours pinned in testdata, so that any change to the algorithm's
+// math is caught. After an intended change, regenerate the files with go test -run TestGolden -update and commit them
+func TestGolden(t *testing.T) {
+	weights := goldenInstance()
+
+	for name, opts := range goldenConfigs {
+		t.Run(name, func(t *testing.T) {
+			opts = append([]Option{WithAnts(goldenAnts), WithSeed(goldenSeed), WithTourCost(SumCost(weights))}, opts...)
+			colony, err := NewAntColony(NewTSPProblem(weights), opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(goldenIters)
+			tour, cost := colony.BestSolution()
+			got := goldenResult{Order: TourOrder(tour), Cost: cost}
+			path := filepath.Join("testdata", name+".golden")
+
+			if *update {
+				data, _ := json.MarshalIndent(got, "", "\t")
+
+				if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				return
+			}
+
+			data, err := os.ReadFile(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var expected goldenResult
+
+			if err := json.Unmarshal(data, &expected); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("got %v, expected %v", got, expected)
+			}
+		})
+	}
+}
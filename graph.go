@@ -1,5 +1,10 @@
 package antcolony
 
+import (
+	"fmt"
+	"sort"
+)
+
 // An edge (a, b) in an undirected graph G
 type Edge struct {
 	A uint
@@ -7,9 +12,191 @@ type Edge struct {
 }
 
 // A graph G = (V, E)
+// Nodes are indexed by uint, but since the indices are used to index into slices (and the pheromone and
+// heuristic matrices are dense n x n), the practical limit on the number of nodes is memory rather than
+// the range of the index type: a graph can never have more nodes than fit in an int.
+// For large graphs with few edges, a SparseProblem stores the matrices with an entry per edge instead.
+// For the same reason the nodes of a graph with n nodes must be numbered 0, ..., n - 1, with Nodes[i] = i;
+// use Compact to renumber a graph whose node indices have gaps
 type Graph struct {
 	// The list of node indices V
 	Nodes []uint
 	// We store the edges in a slice: entry i in the slice is the list of all edges from vertex i
 	Edges [][]Edge
+	// Whether the edges (a, b) and (b, a) may differ, as in asymmetric problems. In an undirected graph they are
+	// the same connection, so anything derived from one direction holds for the other as well: in particular, the
+	// pheromone updates on an edge apply to both directions. In a directed graph the trails of the two directions
+	// are independent, and the heuristics may differ per direction
+	Directed bool
+}
+
+// Construct a complete graph on num_nodes nodes, i.e. there is an edge between every pair of nodes
+// (including self-loops, which are never taken during construction)
+func NewCompleteGraph(num_nodes uint) Graph {
+	nodes := make([]uint, 0)
+	edges := make([][]Edge, 0)
+
+	for i := 0; i < int(num_nodes); i++ {
+		nodes = append(nodes, uint(i))
+		curr_edges := make([]Edge, 0)
+
+		for j := 0; j < int(num_nodes); j++ {
+			curr_edges = append(curr_edges, Edge{A: uint(i), B: uint(j)})
+		}
+
+		edges = append(edges, curr_edges)
+	}
+
+	return Graph{Nodes: nodes, Edges: edges}
+}
+
+// Is there an edge from a to b?
+func (graph *Graph) hasEdge(a uint, b uint) bool {
+	if a >= uint(len(graph.Edges)) {
+		return false
+	}
+
+	for _, edge := range graph.Edges[a] {
+		if edge.B == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A deep copy of the graph, which shares no slices with the original
+func (graph *Graph) clone() Graph {
+	nodes := make([]uint, len(graph.Nodes))
+	copy(nodes, graph.Nodes)
+
+	edges := make([][]Edge, len(graph.Edges))
+
+	for i := range graph.Edges {
+		edges[i] = make([]Edge, len(graph.Edges[i]))
+		copy(edges[i], graph.Edges[i])
+	}
+
+	return Graph{Nodes: nodes, Edges: edges, Directed: graph.Directed}
+}
+
+// Can every node be reached from every other node? A tour visiting all the nodes and returning to its start
+// exists only if so. Edges to nodes outside the graph are ignored
+func (graph *Graph) stronglyConnected() bool {
+	n := len(graph.Nodes)
+
+	if n == 0 {
+		return true
+	}
+
+	reversed := make([][]Edge, n)
+
+	for _, edges := range graph.Edges {
+		for _, edge := range edges {
+			if edge.A < uint(n) && edge.B < uint(n) {
+				reversed[edge.B] = append(reversed[edge.B], Edge{A: edge.B, B: edge.A})
+			}
+		}
+	}
+
+	// Every node is reachable from node 0 and node 0 is reachable from every node exactly when the
+	// graph is strongly connected
+	return reachesAll(graph.Edges, n) && reachesAll(reversed, n)
+}
+
+// Does a depth-first search from node 0 over the adjacency lists reach all n nodes?
+func reachesAll(adjacency [][]Edge, n int) bool {
+	visited := make([]bool, n)
+	visited[0] = true
+	stack := []uint{0}
+	count := 1
+
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if int(curr) >= len(adjacency) {
+			continue
+		}
+
+		for _, edge := range adjacency[curr] {
+			if edge.B < uint(n) && !visited[edge.B] {
+				visited[edge.B] = true
+				stack = append(stack, edge.B)
+				count++
+			}
+		}
+	}
+
+	return count == n
+}
+
+// Check that the nodes are numbered 0, ..., n - 1 in order, and that entry i of the edges lists edges from
+// node i to other nodes of the graph
+func (graph *Graph) validate() error {
+	n := len(graph.Nodes)
+
+	for i, node := range graph.Nodes {
+		if node != uint(i) {
+			return fmt.Errorf("%w: node %d is numbered %d; nodes must be numbered 0, ..., %d in order (see Compact)",
+				ErrInvalidGraph, i, node, n-1)
+		}
+	}
+
+	if len(graph.Edges) != n {
+		return fmt.Errorf("%w: graph has %d edge lists for %d nodes", ErrInvalidGraph, len(graph.Edges), n)
+	}
+
+	for i, edges := range graph.Edges {
+		for _, edge := range edges {
+			if edge.A != uint(i) || edge.B >= uint(n) {
+				return fmt.Errorf("%w: edge (%d, %d) is listed for node %d", ErrInvalidGraph, edge.A, edge.B, i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Renumber a graph whose node indices aren't 0, ..., n - 1 (e.g. nodes {0, 5, 9}), keeping the order of the
+// indices. Returns the renumbered graph and the mapping back from it: node i of the renumbered graph is node
+// mapping[i] of the original. The matrices of the problem have to be renumbered the same way. The edges may be
+// listed under any entry of Edges, but both their ends must be nodes of the graph
+func Compact(graph Graph) (Graph, []uint, error) {
+	mapping := make([]uint, len(graph.Nodes))
+	copy(mapping, graph.Nodes)
+	sort.Slice(mapping, func(i, j int) bool { return mapping[i] < mapping[j] })
+
+	index := make(map[uint]uint, len(mapping))
+
+	for i, node := range mapping {
+		if _, ok := index[node]; ok {
+			return Graph{}, nil, fmt.Errorf("%w: node %d appears more than once", ErrInvalidGraph, node)
+		}
+
+		index[node] = uint(i)
+	}
+
+	nodes := make([]uint, len(mapping))
+	edges := make([][]Edge, len(mapping))
+
+	for i := range nodes {
+		nodes[i] = uint(i)
+		edges[i] = make([]Edge, 0)
+	}
+
+	for _, list := range graph.Edges {
+		for _, edge := range list {
+			a, okA := index[edge.A]
+			b, okB := index[edge.B]
+
+			if !okA || !okB {
+				return Graph{}, nil, fmt.Errorf("%w: edge (%d, %d) has an end outside the graph", ErrInvalidGraph, edge.A, edge.B)
+			}
+
+			edges[a] = append(edges[a], Edge{A: a, B: b})
+		}
+	}
+
+	return Graph{Nodes: nodes, Edges: edges, Directed: graph.Directed}, mapping, nil
 }
@@ -0,0 +1,102 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+)
+
+// A heuristic for prize-collecting and orienteering problems, where moving to a component is more attractive
+// the larger its prize: eta_ij = prize_j / d_ij. The weights must form a square matrix with one prize per component.
+// As with the TSP heuristic, a tiny constant is added to the weights to avoid dividing by zero,
+// and the diagonal is zero since self-loops are never taken
+func PrizeWeightedHeuristic(weights [][]float64, prizes []float64) ([][]float64, error) {
+	n := len(weights)
+
+	if len(prizes) != n {
+		return nil, fmt.Errorf("%w: got %d prizes for %d components", ErrInvalidParams, len(prizes), n)
+	}
+
+	heuristics := make([][]float64, n)
+
+	for i := range weights {
+		if len(weights[i]) != n {
+			return nil, fmt.Errorf("%w: weight matrix row %d has %d entries, expected %d", ErrRaggedMatrix, i, len(weights[i]), n)
+		}
+
+		heuristics[i] = make([]float64, n)
+
+		for j := range weights[i] {
+			if i != j {
+				heuristics[i][j] = prizes[j] / (weights[i][j] + 1e-8)
+			}
+		}
+	}
+
+	return heuristics, nil
+}
+
+// A problem with several heuristics (e.g. distance and road quality) that should be blended into the one used
+// for construction. If a problem implements this, the colony uses the combination of the matrices returned by
+// InitHeuristicSet, as computed by CombineHeuristics, instead of InitHeuristics
+type MultiHeuristicProblem interface {
+	ACOptimizable
+	// The heuristic matrices and their (non-negative) weights
+	InitHeuristicSet() (heuristics [][][]float64, weights []float64)
+}
+
+// Combine several heuristic matrices into one using a weighted geometric mean:
+// eta_ij = (prod_k mats[k]_ij ^ weights[k]) ^ (1 / sum_k weights[k]).
+// A geometric mean keeps the result on the same scale as the inputs, and an edge that any heuristic with a positive
+// weight deems worthless (eta = 0) stays worthless. The matrices must have the same dimensions, and the weights must
+// be non-negative and not all zero
+func CombineHeuristics(mats [][][]float64, weights []float64) ([][]float64, error) {
+	if len(mats) == 0 {
+		return nil, fmt.Errorf("%w: no heuristics to combine", ErrInvalidParams)
+	}
+
+	if len(weights) != len(mats) {
+		return nil, fmt.Errorf("%w: got %d weights for %d heuristics", ErrInvalidParams, len(weights), len(mats))
+	}
+
+	total := 0.0
+
+	for k, weight := range weights {
+		if weight < 0 {
+			return nil, fmt.Errorf("%w: heuristic %d has negative weight %v", ErrInvalidParams, k, weight)
+		}
+
+		total += weight
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: heuristic weights must not all be zero", ErrInvalidParams)
+	}
+
+	combined := make([][]float64, len(mats[0]))
+
+	for i := range mats[0] {
+		combined[i] = make([]float64, len(mats[0][i]))
+
+		for j := range mats[0][i] {
+			combined[i][j] = 1.0
+		}
+	}
+
+	for k, mat := range mats {
+		if len(mat) != len(combined) {
+			return nil, fmt.Errorf("%w: heuristic %d has %d rows, expected %d", ErrRaggedMatrix, k, len(mat), len(combined))
+		}
+
+		for i := range mat {
+			if len(mat[i]) != len(combined[i]) {
+				return nil, fmt.Errorf("%w: heuristic %d row %d has %d entries, expected %d", ErrRaggedMatrix, k, i, len(mat[i]), len(combined[i]))
+			}
+
+			for j := range mat[i] {
+				combined[i][j] *= math.Pow(mat[i][j], weights[k]/total)
+			}
+		}
+	}
+
+	return combined, nil
+}
@@ -0,0 +1,117 @@
+package antcolony
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPrizeWeightedHeuristic(t *testing.T) {
+	weights := [][]float64{
+		{0, 2, 4},
+		{2, 0, 1},
+		{4, 1, 0},
+	}
+
+	heuristics, err := PrizeWeightedHeuristic(weights, []float64{10, 6, 8})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]float64{
+		{0, 3, 2},
+		{5, 0, 8},
+		{2.5, 6, 0},
+	}
+
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(heuristics[i][j]-expected[i][j]) > 1e-6 {
+				t.Errorf("heuristic %d->%d is %v, expected %v", i, j, heuristics[i][j], expected[i][j])
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		weights [][]float64
+		prizes  []float64
+		err     error
+	}{
+		{"too few prizes", weights, []float64{1, 2}, ErrInvalidParams},
+		{"too many prizes", weights, []float64{1, 2, 3, 4}, ErrInvalidParams},
+		{"ragged weights", [][]float64{{0, 1, 1}, {1, 0}, {1, 1, 0}}, []float64{1, 2, 3}, ErrRaggedMatrix},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := PrizeWeightedHeuristic(test.weights, test.prizes); !errors.Is(err, test.err) {
+				t.Errorf("got %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestCombineHeuristics(t *testing.T) {
+	distance := [][]float64{{0, 4}, {9, 0}}
+	quality := [][]float64{{0, 1}, {4, 0}}
+	worthless := [][]float64{{0, 0}, {4, 0}}
+
+	tests := []struct {
+		name     string
+		mats     [][][]float64
+		weights  []float64
+		expected [][]float64
+	}{
+		{"single heuristic", [][][]float64{distance}, []float64{3}, distance},
+		{"equal weights", [][][]float64{distance, quality}, []float64{1, 1}, [][]float64{{0, 2}, {6, 0}}},
+		{"uneven weights", [][][]float64{distance, quality}, []float64{2, 1}, [][]float64{{0, math.Cbrt(16)}, {math.Cbrt(324), 0}}},
+		{"zero weight is ignored", [][][]float64{distance, quality}, []float64{1, 0}, distance},
+		{"worthless edge stays worthless", [][][]float64{distance, worthless}, []float64{1, 1}, [][]float64{{0, 0}, {6, 0}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			combined, err := CombineHeuristics(test.mats, test.weights)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range test.expected {
+				for j := range test.expected[i] {
+					if math.Abs(combined[i][j]-test.expected[i][j]) > 1e-9 {
+						t.Errorf("heuristic %d->%d is %v, expected %v", i, j, combined[i][j], test.expected[i][j])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCombineHeuristicsRejectsInvalidInput(t *testing.T) {
+	square := [][]float64{{0, 1}, {1, 0}}
+
+	tests := []struct {
+		name    string
+		mats    [][][]float64
+		weights []float64
+		err     error
+	}{
+		{"no heuristics", nil, nil, ErrInvalidParams},
+		{"too few weights", [][][]float64{square, square}, []float64{1}, ErrInvalidParams},
+		{"negative weight", [][][]float64{square, square}, []float64{1, -1}, ErrInvalidParams},
+		{"all weights zero", [][][]float64{square, square}, []float64{0, 0}, ErrInvalidParams},
+		{"different row counts", [][][]float64{square, {{0, 1}}}, []float64{1, 1}, ErrRaggedMatrix},
+		{"different row lengths", [][][]float64{square, {{0, 1}, {1}}}, []float64{1, 1}, ErrRaggedMatrix},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := CombineHeuristics(test.mats, test.weights); !errors.Is(err, test.err) {
+				t.Errorf("got %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
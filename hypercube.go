@@ -0,0 +1,37 @@
+package antcolony
+
+// Rescale the initial pheromones so that the largest one is 1, keeping their relative values. In the hyper-cube
+// framework every trail then stays within [0, 1]
+func (colony *AntColony) scaleToHyperCube() {
+	largest := 0.0
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A != edge.B && colony.Pheromones[edge.A][colony.column(edge)] > largest {
+				largest = colony.Pheromones[edge.A][colony.column(edge)]
+			}
+		}
+	}
+
+	for i := range colony.Pheromones {
+		for j := range colony.Pheromones[i] {
+			colony.Pheromones[i][j] /= largest
+		}
+	}
+
+	colony.initialPheromones = copyMatrix(colony.Pheromones)
+}
+
+// The sum of 1 / C over the complete tours of the ants that deposit in this iteration, which normalizes the
+// deposits in the hyper-cube framework
+func (colony *AntColony) hyperCubeNorm(ants []Ant) float64 {
+	norm := 0.0
+
+	for i := range ants {
+		if colony.IsComplete(&ants[i]) {
+			norm += 1 / colony.tourCost(ants[i].tour)
+		}
+	}
+
+	return norm
+}
@@ -0,0 +1,64 @@
+package antcolony
+
+// An ant that constructs its tour one step at a time, exposing the choice it faces at every step.
+// This is meant for teaching and debugging, e.g. to verify the selection math; RunSimulation doesn't use it
+type InspectableAnt struct {
+	ant    Ant
+	colony *AntColony
+}
+
+// Create an ant of the colony that starts where the colony's ants would (following the fixed prefix if there
+// is one). The ant draws from the colony's random source, and its tour doesn't affect the colony: it's neither
+// recorded nor deposited
+func NewInspectableAnt(colony *AntColony) *InspectableAnt {
+	inspectable := &InspectableAnt{ant: colony.newAnt(), colony: colony}
+	inspectable.ant.begin(colony)
+
+	return inspectable
+}
+
+// Make a single move. Returns the chosen component, the probability of choosing each of the candidates, and whether
+// the ant is done, either because it closed its tour or because it's stuck with no feasible candidates. The
+// probabilities are the normalized scores, as used by Roulette selection; the other selection methods only use
+// the scores' order. Once the ant is done, Step doesn't move and returns the current component and no probabilities
+func (inspectable *InspectableAnt) Step() (chosen uint, probs map[uint]float64, done bool) {
+	ant := &inspectable.ant
+	colony := inspectable.colony
+
+	if ant.closed(colony) {
+		return ant.currComponent, nil, true
+	}
+
+	candidates, scores := ant.candidates(colony)
+
+	if len(candidates) == 0 {
+		return ant.currComponent, nil, true
+	}
+
+	probs = make(map[uint]float64, len(candidates))
+
+	for i, p := range rouletteProbabilities(scores) {
+		probs[candidates[i]] += p
+	}
+
+	chosen = colony.selectNext(colony.rng, candidates, scores)
+	ant.move(Edge{A: ant.currComponent, B: chosen})
+
+	// Look ahead, so the caller knows when to stop without another call
+	done = ant.closed(colony) || len(ant.feasibleEdges(colony)) == 0
+
+	return chosen, probs, done
+}
+
+// The component the ant is currently at
+func (inspectable *InspectableAnt) Current() uint {
+	return inspectable.ant.currComponent
+}
+
+// A copy of the tour constructed so far
+func (inspectable *InspectableAnt) Tour() []Edge {
+	tour := make([]Edge, len(inspectable.ant.tour))
+	copy(tour, inspectable.ant.tour)
+
+	return tour
+}
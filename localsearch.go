@@ -0,0 +1,68 @@
+package antcolony
+
+// A local search that improves the ants' tours before they deposit, as set with WithLocalSearch. Improve gets a
+// complete tour and returns an improved one (or the same tour if it can't improve it), without modifying the input
+type LocalSearch interface {
+	Improve(tour []Edge, problem ACOptimizable) []Edge
+}
+
+// The 2-opt local search as a LocalSearch, using the edge costs of a CostProblem (such as TSPProblem). Like TwoOpt,
+// it assumes symmetric costs. Tours of problems that don't give their edge costs are returned as they are
+type TwoOptSearch struct{}
+
+func (TwoOptSearch) Improve(tour []Edge, problem ACOptimizable) []Edge {
+	costs, ok := problem.(CostProblem)
+
+	if !ok {
+		return tour
+	}
+
+	return twoOpt(tour, func(a, b uint) float64 { return costs.Cost(Edge{A: a, B: b}) })
+}
+
+// Improve a tour with the 2-opt local search: repeatedly remove two edges (a, b) and (c, d) and reconnect
+// the tour as (a, c) and (b, d) whenever this shortens it, until no such move improves the tour.
+// Reconnecting reverses the segment between b and c, so this assumes symmetric weights.
+// The input tour isn't modified
+func TwoOpt(tour []Edge, weights [][]float64) []Edge {
+	return twoOpt(tour, func(a, b uint) float64 { return weights[a][b] })
+}
+
+// 2-opt with the cost of going from a to b given by cost
+func twoOpt(tour []Edge, cost func(a, b uint) float64) []Edge {
+	order := TourOrder(tour)
+	n := len(order)
+	improved := true
+
+	for improved {
+		improved = false
+
+		for i := 0; i < n-1; i++ {
+			for j := i + 2; j < n; j++ {
+				// These two edges are adjacent, so swapping them would do nothing
+				if i == 0 && j == n-1 {
+					continue
+				}
+
+				a, b := order[i], order[i+1]
+				c, d := order[j], order[(j+1)%n]
+				delta := cost(a, c) + cost(b, d) - cost(a, b) - cost(c, d)
+
+				// Only accept moves that improve the tour by more than rounding noise
+				if delta < -1e-12 {
+					reverse(order[i+1 : j+1])
+					improved = true
+				}
+			}
+		}
+	}
+
+	return tourFromOrder(order)
+}
+
+// Reverse a slice in place
+func reverse(order []uint) {
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+}
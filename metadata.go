@@ -0,0 +1,207 @@
+package antcolony
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// The effective parameters of a run, as reported by RunMetadata. Every option that changes how a run goes should be
+// reflected here
+type runParams struct {
+	Alpha             float64 `json:"alpha"`
+	Beta              float64 `json:"beta"`
+	Rho               float64 `json:"rho"`
+	Selection         string  `json:"selection"`
+	TournamentSize    int     `json:"tournament_size"`
+	TieBreak          string  `json:"tie_break"`
+	ToursPerAnt       int     `json:"tours_per_ant"`
+	LogSpace          bool    `json:"log_space"`
+	PheromoneDisabled bool    `json:"pheromone_disabled"`
+	Perception        float64 `json:"perception"`
+	Q0                float64 `json:"q0"`
+	LocalDecay        float64 `json:"local_decay"`
+	HyperCube         bool    `json:"hyper_cube"`
+	Workers           int     `json:"workers"`
+	Epsilon           float64 `json:"epsilon"`
+	CandidateCount    int     `json:"candidate_count"`
+	DepositBestTour   bool    `json:"deposit_best_tour"`
+	BatchedScoring    bool    `json:"batched_scoring"`
+	GreedySeeds       bool    `json:"greedy_seeds"`
+	AntMemory         float64 `json:"ant_memory"`
+	RowNormalization  float64 `json:"row_normalization"`
+	MaxSingleDeposit  float64 `json:"max_single_deposit"`
+	RestartAfter      int     `json:"restart_after"`
+	RestartKeepBest   bool    `json:"restart_keep_best"`
+	RestartBias       float64 `json:"restart_bias"`
+	EarlyStopping     int     `json:"early_stopping"`
+	TourBudget        int     `json:"tour_budget"`
+	IterationTimeout  float64 `json:"iteration_timeout_ms"`
+	// Pointers, since these are null when disabled
+	ProgressiveBeta *float64             `json:"progressive_beta"`
+	LongEdgePenalty *longEdgePenaltySpec `json:"long_edge_penalty"`
+	LocalSearch     *string              `json:"local_search"`
+	Constructor     *string              `json:"constructor"`
+}
+
+type longEdgePenaltySpec struct {
+	Percentile float64 `json:"percentile"`
+	Factor     float64 `json:"factor"`
+}
+
+// A record of a run, as returned by RunMetadata
+type runMetadata struct {
+	// A pointer, since a source set with WithRandSource has no known seed
+	Seed     *int64 `json:"seed"`
+	Replayed bool   `json:"replayed"`
+	Ants     int    `json:"ants"`
+	Strategy string `json:"strategy"`
+	// The strategy's exported fields, or null if they can't be marshalled
+	StrategyParams json.RawMessage `json:"strategy_params"`
+	Params         runParams       `json:"params"`
+	Iterations     int             `json:"iterations"`
+	Evaluations    int             `json:"evaluations"`
+	// Pointers, since there is no best tour before one is completed
+	BestCost      *float64 `json:"best_cost"`
+	BestIteration *int     `json:"best_iteration"`
+	TimeToBestMs  *float64 `json:"time_to_best_ms"`
+}
+
+// Describe the run so far as JSON, e.g. to log it for experiment tracking. The parameters are the effective ones
+// (after the defaults and any adaptive control), so the record is self-describing. In params, a count, size, weight
+// or limit of 0 means the feature it sets is disabled:
+//
+//	{
+//	  "seed": 1337,                   // the seed of the colony's random source, or null for a custom source
+//	  "replayed": false,              // whether the draws were replayed from a recorded stream
+//	  "ants": 20,                     // the current number of ants
+//	  "strategy": "antcolony.AntCycleStrategy",
+//	  "strategy_params": {},          // the strategy's exported fields, e.g. {"Weight": 10} for the elitist one
+//	  "params": {"alpha": 1, "beta": 3, "rho": 0.5, "selection": "roulette", "tournament_size": 2,
+//	             "tie_break": "keep-first", "tours_per_ant": 1, "log_space": false,
+//	             "pheromone_disabled": false, "perception": 1, "q0": 0, "local_decay": 0,
+//	             "hyper_cube": false, "workers": 1, "epsilon": 1e-9, "candidate_count": 0,
+//	             "deposit_best_tour": false, "batched_scoring": false, "greedy_seeds": false,
+//	             "ant_memory": 0, "row_normalization": 0, "max_single_deposit": 0,
+//	             "restart_after": 0, "restart_keep_best": true, "restart_bias": 0, "early_stopping": 0,
+//	             "tour_budget": 0, "iteration_timeout_ms": 0,
+//	             "progressive_beta": null,    // the beta floor, or null if beta doesn't decay
+//	             "long_edge_penalty": null,   // {"percentile": 90, "factor": 0.1}, or null if disabled
+//	             "local_search": null,        // the type of the local search or constructor, or null
+//	             "constructor": null},
+//	  "iterations": 100,              // the iterations run since the colony was created or reset
+//	  "evaluations": 2000,            // the tours constructed, i.e. the number of cost evaluations
+//	  "best_cost": 42.5,              // the cost of the best tour, or null if none was completed
+//	  "best_iteration": 37,           // the iterations completed before the best tour was found
+//	  "time_to_best_ms": 12.3         // the time from the colony's creation (or reset) to the best tour
+//	}
+func (colony *AntColony) RunMetadata() []byte {
+	metadata := runMetadata{
+		Replayed: colony.replay != nil,
+		Ants:     len(colony.ants),
+		Strategy: fmt.Sprintf("%T", colony.strategy),
+		Params: runParams{
+			Alpha:             colony.alpha,
+			Beta:              colony.beta,
+			Rho:               colony.rho,
+			Selection:         selectionName(colony.selection),
+			TournamentSize:    colony.tournamentSize,
+			TieBreak:          tieBreakName(colony.tieBreak),
+			ToursPerAnt:       colony.toursPerAnt,
+			LogSpace:          colony.logSpace,
+			PheromoneDisabled: colony.pheromoneDisabled,
+			Perception:        colony.perception,
+			Q0:                colony.q0,
+			LocalDecay:        colony.localDecay,
+			HyperCube:         colony.hyperCube,
+			Workers:           colony.workers,
+			Epsilon:           colony.epsilon,
+			CandidateCount:    colony.candidateCount,
+			DepositBestTour:   colony.depositBestTour,
+			BatchedScoring:    colony.batchedScoring,
+			GreedySeeds:       colony.greedySeeds,
+			AntMemory:         colony.memoryWeight,
+			RowNormalization:  colony.rowTarget,
+			MaxSingleDeposit:  colony.maxSingleDeposit,
+			RestartAfter:      colony.restartAfter,
+			RestartKeepBest:   colony.restartKeepBest,
+			RestartBias:       colony.restartBias,
+			EarlyStopping:     colony.stopAfter,
+			TourBudget:        colony.tourBudget,
+			IterationTimeout:  float64(colony.iterationTimeout.Microseconds()) / 1000,
+			LocalSearch:       typeName(colony.localSearch),
+			Constructor:       typeName(colony.constructor),
+		},
+		Iterations:  colony.iterations,
+		Evaluations: colony.toursConstructed,
+	}
+
+	if colony.source == nil {
+		metadata.Seed = &colony.seed
+	}
+
+	if params, err := json.Marshal(colony.strategy); err == nil {
+		metadata.StrategyParams = params
+	}
+
+	if colony.progressiveBeta {
+		metadata.Params.ProgressiveBeta = &colony.betaFloor
+	}
+
+	if colony.longEdgePenalty {
+		metadata.Params.LongEdgePenalty = &longEdgePenaltySpec{Percentile: colony.longEdgePercentile, Factor: colony.longEdgeFactor}
+	}
+
+	if _, cost := colony.overallBest(); !math.IsInf(cost, 1) {
+		iteration, elapsed := colony.bestIteration, colony.timeToBest
+
+		// The best tour was forgotten by a restart
+		if colony.improves(colony.overallCost, colony.bestCost) {
+			iteration, elapsed = colony.overallIteration, colony.overallTime
+		}
+
+		ms := float64(elapsed.Microseconds()) / 1000
+		metadata.BestCost = &cost
+		metadata.BestIteration = &iteration
+		metadata.TimeToBestMs = &ms
+	}
+
+	// The record only holds plain values (and the strategy's parameters, already marshalled), so marshalling
+	// can't fail
+	data, _ := json.Marshal(metadata)
+
+	return data
+}
+
+// The dynamic type of v, or nil if v is nil
+func typeName(v any) *string {
+	if v == nil {
+		return nil
+	}
+
+	name := fmt.Sprintf("%T", v)
+
+	return &name
+}
+
+func selectionName(method SelectionMethod) string {
+	switch method {
+	case Tournament:
+		return "tournament"
+	case RankProportional:
+		return "rank"
+	default:
+		return "roulette"
+	}
+}
+
+func tieBreakName(policy TieBreakPolicy) string {
+	switch policy {
+	case TiePreferDiverse:
+		return "prefer-diverse"
+	case TieKeepAll:
+		return "keep-all"
+	default:
+		return "keep-first"
+	}
+}
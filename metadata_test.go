@@ -0,0 +1,75 @@
+package antcolony
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRunMetadataReportsTheOptions(t *testing.T) {
+	weights := ringWeights(t, 6)
+	tests := []struct {
+		name     string
+		opts     []Option
+		key      string
+		expected any
+	}{
+		{"q0", []Option{WithPseudoRandomProportional(0.9)}, "q0", 0.9},
+		{"local decay", []Option{WithLocalPheromoneUpdate(0.1)}, "local_decay", 0.1},
+		{"hyper-cube", []Option{WithHyperCube(true)}, "hyper_cube", true},
+		{"workers", []Option{WithWorkers(4)}, "workers", 4.0},
+		{"epsilon", []Option{WithEpsilon(1e-6)}, "epsilon", 1e-6},
+		{"candidate lists", []Option{WithCandidateLists(3)}, "candidate_count", 3.0},
+		{"restarts", []Option{WithRestartOnStagnation(5)}, "restart_after", 5.0},
+		{"restart keep best", []Option{WithRestartKeepBest(false)}, "restart_keep_best", false},
+		{"restart bias", []Option{WithRestartBias(0.5)}, "restart_bias", 0.5},
+		{"early stopping", []Option{WithEarlyStopping(7)}, "early_stopping", 7.0},
+		{"max single deposit", []Option{WithMaxSingleDeposit(2)}, "max_single_deposit", 2.0},
+		{"progressive beta", []Option{WithProgressiveBeta(0.5)}, "progressive_beta", 0.5},
+		{"no local search", nil, "local_search", nil},
+		{"local search", []Option{WithLocalSearch(TwoOptSearch{})}, "local_search", "antcolony.TwoOptSearch"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colony, err := NewAntColony(NewTSPProblem(weights), append([]Option{WithSeed(1)}, test.opts...)...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var metadata struct {
+				Params map[string]any `json:"params"`
+			}
+
+			if err := json.Unmarshal(colony.RunMetadata(), &metadata); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := metadata.Params[test.key]; got != test.expected {
+				t.Errorf("got %s = %v, expected %v", test.key, got, test.expected)
+			}
+		})
+	}
+}
+
+func TestRunMetadataReportsTheStrategyParameters(t *testing.T) {
+	weights := ringWeights(t, 6)
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithPheromoneStrategy(RankStrategy{Width: 4}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var metadata struct {
+		Strategy       string         `json:"strategy"`
+		StrategyParams map[string]any `json:"strategy_params"`
+	}
+
+	if err := json.Unmarshal(colony.RunMetadata(), &metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	if metadata.Strategy != "antcolony.RankStrategy" || metadata.StrategyParams["Width"] != 4.0 {
+		t.Errorf("got strategy %s with %v, expected antcolony.RankStrategy with width 4", metadata.Strategy, metadata.StrategyParams)
+	}
+}
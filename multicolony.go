@@ -0,0 +1,138 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Which sub-colonies exchange their best tours during migration
+type MigrationTopology int
+
+const (
+	// Every colony receives the best tour of the previous colony, wrapping around at the start
+	RingTopology MigrationTopology = iota
+	// Every colony receives the overall best tour among all the colonies
+	FullyConnectedTopology
+)
+
+// An island model: several sub-colonies, each with its own parameters and strategy, that search independently
+// and periodically exchange their best tours (migration).
+//
+// The colonies run in parallel for interval iterations, then wait for each other and migrate. During migration,
+// each colony receives the best tour of its source(s) according to the topology, as they were at the end of the
+// interval. If the migrant is better than the receiver's best-so-far, it becomes the receiver's best-so-far and
+// is deposited on the receiver's trails with 1 / C, where C is its cost under the receiver's cost function.
+// Since every colony has its own random source and migrations happen at fixed points in a fixed order, runs are
+// reproducible when every colony is seeded, regardless of how the goroutines are scheduled
+type MultiColony struct {
+	colonies []*AntColony
+	interval int
+	topology MigrationTopology
+}
+
+// Combine distinct colonies built on the same graph into an island model that migrates every interval iterations.
+// The colonies run concurrently, so they must not be used elsewhere while the multi-colony runs
+func NewMultiColony(colonies []*AntColony, interval int, topology MigrationTopology) (*MultiColony, error) {
+	if len(colonies) == 0 {
+		return nil, fmt.Errorf("%w: a multi-colony needs at least one colony", ErrInvalidParams)
+	}
+
+	if interval < 1 {
+		return nil, fmt.Errorf("%w: migration interval must be at least 1, got %d", ErrInvalidParams, interval)
+	}
+
+	if topology != RingTopology && topology != FullyConnectedTopology {
+		return nil, fmt.Errorf("%w: unknown migration topology %d", ErrInvalidParams, topology)
+	}
+
+	for i, colony := range colonies {
+		if len(colony.constructionGraph.Nodes) != len(colonies[0].constructionGraph.Nodes) {
+			return nil, fmt.Errorf("%w: colony %d has %d nodes, but colony 0 has %d", ErrInvalidParams, i, len(colony.constructionGraph.Nodes), len(colonies[0].constructionGraph.Nodes))
+		}
+	}
+
+	return &MultiColony{colonies: colonies, interval: interval, topology: topology}, nil
+}
+
+// Run every colony for num_iters iterations, migrating every interval iterations
+func (multi *MultiColony) RunSimulation(num_iters int) {
+	for done := 0; done < num_iters; done += multi.interval {
+		iters := multi.interval
+
+		if num_iters-done < iters {
+			iters = num_iters - done
+		}
+
+		var wg sync.WaitGroup
+
+		for _, colony := range multi.colonies {
+			wg.Add(1)
+
+			go func(colony *AntColony) {
+				defer wg.Done()
+				colony.RunSimulation(iters)
+			}(colony)
+		}
+
+		wg.Wait()
+		multi.migrate()
+	}
+}
+
+// Exchange the best tours between the colonies according to the topology
+func (multi *MultiColony) migrate() {
+	// Take a snapshot of the best tours first, so that the order of the migrations doesn't matter
+	tours := make([][]Edge, len(multi.colonies))
+
+	for i, colony := range multi.colonies {
+		tours[i], _ = colony.BestSolution()
+	}
+
+	overall, _ := multi.BestSolution()
+
+	for i, colony := range multi.colonies {
+		var migrant []Edge
+
+		switch multi.topology {
+		case RingTopology:
+			migrant = tours[(i-1+len(tours))%len(tours)]
+		case FullyConnectedTopology:
+			migrant = overall
+		}
+
+		if len(migrant) == 0 {
+			continue
+		}
+
+		cost := colony.tourCost(migrant)
+
+		if colony.improves(cost, colony.costToBeat()) {
+			colony.setBest(migrant, cost)
+			colony.optima = [][]Edge{colony.bestTour}
+			colony.DepositTour(migrant, 1.0/cost)
+		}
+	}
+}
+
+// The best tour found by any of the colonies, and its cost under that colony's cost function
+func (multi *MultiColony) BestSolution() ([]Edge, float64) {
+	var best []Edge
+	bestCost := math.Inf(1)
+
+	for _, colony := range multi.colonies {
+		tour, cost := colony.BestSolution()
+
+		if cost < bestCost {
+			best = tour
+			bestCost = cost
+		}
+	}
+
+	return best, bestCost
+}
+
+// The sub-colonies
+func (multi *MultiColony) Colonies() []*AntColony {
+	return multi.colonies
+}
@@ -0,0 +1,845 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// An Option configures an AntColony at construction. Options are applied after the problem's graph,
+// pheromones and heuristics are set up, so they can be validated against them
+type Option func(*AntColony) error
+
+// Seed the colony's random source, making runs reproducible
+func WithSeed(seed int64) Option {
+	return func(colony *AntColony) error {
+		colony.seed = seed
+
+		return nil
+	}
+}
+
+// Draw the colony's random numbers from source instead of a source seeded with WithSeed (or the current time), e.g.
+// to share a source that the caller controls. The colony draws from it without synchronization, so the source
+// must not be used elsewhere while the colony runs
+func WithRandSource(source rand.Source) Option {
+	return func(colony *AntColony) error {
+		if source == nil {
+			return fmt.Errorf("%w: random source must not be nil", ErrInvalidParams)
+		}
+
+		colony.source = source
+
+		return nil
+	}
+}
+
+// Use n ants instead of one per component. The number of ants is passed on to the problem's InitPheromones
+func WithAnts(n int) Option {
+	return func(colony *AntColony) error {
+		if n < 1 {
+			return fmt.Errorf("%w: number of ants must be at least 1, got %d", ErrInvalidParams, n)
+		}
+
+		colony.num_ants = uint(n)
+
+		return nil
+	}
+}
+
+// Set the pheromone weight alpha (1 by default)
+func WithAlpha(alpha float64) Option {
+	return func(colony *AntColony) error {
+		if err := validateWeight("alpha", alpha); err != nil {
+			return err
+		}
+
+		colony.alpha = alpha
+
+		return nil
+	}
+}
+
+// Set the heuristic weight beta (3 by default)
+func WithBeta(beta float64) Option {
+	return func(colony *AntColony) error {
+		if err := validateWeight("beta", beta); err != nil {
+			return err
+		}
+
+		colony.beta = beta
+
+		return nil
+	}
+}
+
+// Set the evaporation rate rho, the fraction of the pheromone that evaporates every iteration (0.5 by default)
+func WithEvaporation(rho float64) Option {
+	return func(colony *AntColony) error {
+		if err := validateRho(rho); err != nil {
+			return err
+		}
+
+		colony.rho = rho
+
+		return nil
+	}
+}
+
+// Record every random draw made by the colony into recorder, so that a surprising run can be replayed exactly
+// with WithRNGReplay(recorder.Stream())
+func WithRNGRecorder(recorder *RNGRecorder) Option {
+	return func(colony *AntColony) error {
+		if recorder == nil {
+			return fmt.Errorf("%w: RNG recorder must not be nil", ErrInvalidParams)
+		}
+
+		colony.recorder = recorder
+
+		return nil
+	}
+}
+
+// Take the colony's random draws from a stream recorded with WithRNGRecorder, reproducing the recorded run exactly,
+// even when the seed is unknown. The stream is replayed in order, one value per draw: if a code change alters the
+// number or order of the draws, every later draw receives a different value than it did in the recorded run, and
+// the trajectories diverge from that point on. Once the stream is exhausted, the draws come from the colony's seed
+func WithRNGReplay(stream []int64) Option {
+	return func(colony *AntColony) error {
+		colony.replay = make([]int64, len(stream))
+		copy(colony.replay, stream)
+
+		return nil
+	}
+}
+
+// Use a custom cost function for tours instead of the sum of the edge costs, e.g. BottleneckCost
+func WithTourCost(cost TourCost) Option {
+	return func(colony *AntColony) error {
+		colony.costFunc = cost
+
+		return nil
+	}
+}
+
+// Budget RunSimulation by the total number of tours constructed instead of the number of iterations:
+// iterations are run until num_ants * iterations reaches n. This makes runs with different numbers of ants
+// comparable. The last iteration always completes, so slightly more than n tours may be constructed;
+// use IterationsRun to find out how many iterations the budget translated to
+func WithTotalTourBudget(n int) Option {
+	return func(colony *AntColony) error {
+		if n < 1 {
+			return fmt.Errorf("%w: tour budget must be at least 1, got %d", ErrInvalidParams, n)
+		}
+
+		colony.tourBudget = n
+
+		return nil
+	}
+}
+
+// Fix a prefix of components that every ant visits, in order, before optimizing the remainder of its tour,
+// e.g. to re-optimize the rest of a route whose first stops are already committed to.
+// Every component must be visited at most once, and consecutive components must be connected in the graph
+func WithFixedPrefix(prefix []uint) Option {
+	return func(colony *AntColony) error {
+		n := uint(len(colony.constructionGraph.Nodes))
+		seen := make(map[uint]bool)
+
+		for i, component := range prefix {
+			if component >= n {
+				return fmt.Errorf("%w: prefix component %d is out of range", ErrInfeasibleTour, component)
+			}
+
+			if seen[component] {
+				return fmt.Errorf("%w: prefix component %d appears more than once", ErrInfeasibleTour, component)
+			}
+
+			seen[component] = true
+
+			if i > 0 && !colony.constructionGraph.hasEdge(prefix[i-1], component) {
+				return fmt.Errorf("%w: prefix edge (%d, %d) is not in the construction graph", ErrInfeasibleTour, prefix[i-1], component)
+			}
+		}
+
+		colony.fixedPrefix = make([]uint, len(prefix))
+		copy(colony.fixedPrefix, prefix)
+
+		return nil
+	}
+}
+
+// Use a custom pheromone update strategy instead of the default AntCycleStrategy
+func WithPheromoneStrategy(strategy PheromoneStrategy) Option {
+	return func(colony *AntColony) error {
+		if strategy == nil {
+			return fmt.Errorf("%w: pheromone strategy must not be nil", ErrInvalidParams)
+		}
+
+		// The built-in strategies with parameters check them here
+		if validated, ok := strategy.(interface{ validate() error }); ok {
+			if err := validated.validate(); err != nil {
+				return err
+			}
+		}
+
+		colony.strategy = strategy
+
+		return nil
+	}
+}
+
+// Vary the elitist weight e over a run, e.g. decaying it so that early iterations exploit the best-so-far tour
+// strongly and later ones relax. schedule is called in every iteration with the number of iterations run so far
+// in the current call to RunSimulation (starting at 0) and the total number of iterations in it. With
+// WithTotalTourBudget the total is estimated from the initial number of ants. This requires the ElitistStrategy,
+// whose Weight is used as a constant otherwise. Since the elitist deposit is added after the ant deposits, a
+// strategy that bounds the trails (as in MMAS) should clamp after it, in which case a large e just saturates the
+// best tour's edges at the upper bound and a decaying schedule releases them below it later in the run
+func WithElitistSchedule(schedule func(iter, total int) float64) Option {
+	return func(colony *AntColony) error {
+		if schedule == nil {
+			return fmt.Errorf("%w: elitist schedule must not be nil", ErrInvalidParams)
+		}
+
+		colony.elitistSchedule = schedule
+
+		return nil
+	}
+}
+
+// Call callback at the end of every iteration with the statistics of the tours constructed in it, e.g. to log
+// or plot the best, mean and worst costs as the colony converges
+func WithIterationCallback(callback func(stats IterationStats)) Option {
+	return func(colony *AntColony) error {
+		colony.onIteration = callback
+
+		return nil
+	}
+}
+
+// Add an observer that's called at the end of every iteration with the colony and the iteration's statistics.
+// Unlike WithIterationCallback, the option may be given several times to add several observers
+func WithObserver(observer IterationObserver) Option {
+	return func(colony *AntColony) error {
+		if observer == nil {
+			return fmt.Errorf("%w: observer must not be nil", ErrInvalidParams)
+		}
+
+		colony.observers = append(colony.observers, observer)
+
+		return nil
+	}
+}
+
+// Steer the ants away from very long edges: during construction, the score of every candidate edge whose cost is
+// above the given percentile (in (0, 100]) of all the edge costs is multiplied by factor (in [0, 1]). The costs
+// are the reciprocals of the heuristics, and the percentile is computed once here with the nearest-rank method
+// (and again by UpdateHeuristics). With a factor of 0 the long edges are only taken when nothing else is left
+func WithLongEdgePenalty(percentile, factor float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(percentile) || percentile <= 0 || percentile > 100 {
+			return fmt.Errorf("%w: long edge percentile must be in (0, 100], got %f", ErrInvalidParams, percentile)
+		}
+
+		if math.IsNaN(factor) || factor < 0 || factor > 1 {
+			return fmt.Errorf("%w: long edge penalty factor must be in [0, 1], got %f", ErrInvalidParams, factor)
+		}
+
+		colony.longEdgePenalty = true
+		colony.longEdgePercentile = percentile
+		colony.longEdgeFactor = factor
+		colony.longEdgeThreshold = colony.edgeCostPercentile(percentile)
+
+		return nil
+	}
+}
+
+// Warm-start the colony from a known tour: before the first iteration, it's deposited on like an ant's tour
+// (1 / C on each of its edges) and recorded as the best-so-far. This biases the search towards the tour;
+// to only measure whether the colony can beat it, use WithBaselineBest instead. The tour must be a Hamiltonian
+// cycle over the components that only uses edges of the construction graph
+func WithInitialTour(tour []Edge) Option {
+	return func(colony *AntColony) error {
+		if err := colony.validateGraphTour(tour); err != nil {
+			return fmt.Errorf("invalid initial tour: %w", err)
+		}
+
+		colony.initialTour = make([]Edge, len(tour))
+		copy(colony.initialTour, tour)
+
+		return nil
+	}
+}
+
+// Set a known tour with the given cost as a baseline the colony has to beat, without depositing any pheromones on
+// it. Unlike WithInitialTour, the search isn't biased towards the tour: it never becomes the best-so-far that the
+// elitist and best-so-far strategies deposit on, and only tours that beat it are recorded as the best, which makes it
+// a clean benchmark. BestSolution reports the baseline until a tour beats it. The tour must be valid as for
+// WithInitialTour
+func WithBaselineBest(tour []Edge, cost float64) Option {
+	return func(colony *AntColony) error {
+		if err := colony.validateGraphTour(tour); err != nil {
+			return fmt.Errorf("invalid baseline tour: %w", err)
+		}
+
+		if math.IsNaN(cost) {
+			return fmt.Errorf("%w: baseline cost must not be NaN", ErrInvalidParams)
+		}
+
+		colony.baselineTour = make([]Edge, len(tour))
+		copy(colony.baselineTour, tour)
+		colony.baselineCost = cost
+
+		return nil
+	}
+}
+
+// Scale the problem's initial pheromones by an approximation of each edge's betweenness, so that edges bridging
+// parts of a structured graph start out more attractive. Shortest paths (with the edge costs as lengths) are only
+// computed from samples sources drawn from the colony RNG, and the pheromone on an edge on b of them is multiplied
+// by 1 + b / b_max, at most doubling it. Each source costs O(E log n + n^2). The scaling is applied on top of
+// whatever InitPheromones returns, and Reset restores the scaled pheromones
+func WithBetweennessInit(samples int) Option {
+	return func(colony *AntColony) error {
+		if samples < 1 {
+			return fmt.Errorf("%w: betweenness samples must be at least 1, got %d", ErrInvalidParams, samples)
+		}
+
+		colony.betweennessSamples = samples
+
+		return nil
+	}
+}
+
+// Have each ant construct k tours per iteration, each from a new random start, to sample more tours without
+// allocating more ants. All the tours are recorded and count towards the tour budget. If bestOnly is false,
+// every tour is passed on to the pheromone strategy, so the colony deposits as if it had k times as many ants;
+// otherwise only each ant's best complete tour is, which reinforces good tours more selectively.
+// The default is a single tour per ant
+func WithToursPerAnt(k int, bestOnly bool) Option {
+	return func(colony *AntColony) error {
+		if k < 1 {
+			return fmt.Errorf("%w: tours per ant must be at least 1, got %d", ErrInvalidParams, k)
+		}
+
+		colony.toursPerAnt = k
+		colony.depositBestTour = bestOnly
+
+		return nil
+	}
+}
+
+// Cap the time the ants spend constructing their tours in each iteration. The cap is soft: it's checked before
+// each tour, so the tour being constructed when it passes is finished, the remaining ones are skipped, and the
+// pheromones are updated from the tours completed so far. At least one tour is constructed in every iteration.
+// This bounds the latency of runaway iterations at the cost of fewer tours, and thus a weaker pheromone signal,
+// in the iterations that hit the cap. In a MultiColony each colony's iterations are capped separately
+func WithIterationTimeout(timeout time.Duration) Option {
+	return func(colony *AntColony) error {
+		if timeout <= 0 {
+			return fmt.Errorf("%w: iteration timeout must be positive, got %v", ErrInvalidParams, timeout)
+		}
+
+		colony.iterationTimeout = timeout
+
+		return nil
+	}
+}
+
+// Restrict each construction step to the k nearest neighbours of the current component (its candidate list),
+// falling back to all the feasible components only when none of the candidates is feasible. This speeds up
+// large instances and focuses the search on short edges. The lists respect the graph's Directed flag: in a
+// directed graph they're built from the outgoing edges' costs, and the incoming edges get separate lists
+func WithCandidateLists(k int) Option {
+	return func(colony *AntColony) error {
+		if k < 1 {
+			return fmt.Errorf("%w: candidate list size must be at least 1, got %d", ErrInvalidParams, k)
+		}
+
+		colony.candidateCount = k
+		colony.buildCandidateLists()
+
+		return nil
+	}
+}
+
+// Restart the search once the best-so-far tour hasn't improved for iters iterations in a row: the pheromones are
+// reset to their initial values, so the colony can escape the region it converged to
+func WithRestartOnStagnation(iters int) Option {
+	return func(colony *AntColony) error {
+		if iters < 1 {
+			return fmt.Errorf("%w: stagnation limit must be at least 1, got %d", ErrInvalidParams, iters)
+		}
+
+		colony.restartAfter = iters
+
+		return nil
+	}
+}
+
+// Bias the restarts set with WithRestartOnStagnation towards the best tour of the run: after the pheromones are reset,
+// weight times the mean initial pheromone is deposited on its edges, so the fresh search starts near it instead of
+// from scratch. 0 (the default) restarts without a bias; a weight around 1 doubles the initial trails of the tour
+func WithRestartBias(weight float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(weight) || math.IsInf(weight, 0) || weight < 0 {
+			return fmt.Errorf("%w: restart bias must be non-negative and finite, got %f", ErrInvalidParams, weight)
+		}
+
+		colony.restartBias = weight
+
+		return nil
+	}
+}
+
+// Stop running iterations once the best tour of the whole run hasn't improved for iters iterations in a row. Restarts
+// don't reset the count, so with WithRestartOnStagnation a restart only delays the stop if it leads to a better tour.
+// The count carries over between calls to RunSimulation
+func WithEarlyStopping(iters int) Option {
+	return func(colony *AntColony) error {
+		if iters < 1 {
+			return fmt.Errorf("%w: early stopping limit must be at least 1, got %d", ErrInvalidParams, iters)
+		}
+
+		colony.stopAfter = iters
+
+		return nil
+	}
+}
+
+// Whether the best-so-far tour survives a restart. Keeping it (the default) is safe: strategies that reinforce the
+// best-so-far keep steering the search towards it. Forgetting it lets the search start genuinely afresh, which can
+// help on deceptive instances where the best-so-far is a trap, at the risk of spending the rest of the run in worse
+// regions. Either way, BestSolution reports the best tour of the whole run
+func WithRestartKeepBest(keep bool) Option {
+	return func(colony *AntColony) error {
+		colony.restartKeepBest = keep
+
+		return nil
+	}
+}
+
+// Clamp every individual deposit to at most max before it's added to the trails, so that a single very short tour
+// can't leave a spike that dominates all the others. This bounds the increment of each deposit (by an ant, the
+// elitist reinforcement, an initial tour or a migrant), not the total pheromone an edge can accumulate
+func WithMaxSingleDeposit(max float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(max) || math.IsInf(max, 0) || max <= 0 {
+			return fmt.Errorf("%w: maximum single deposit must be positive and finite, got %f", ErrInvalidParams, max)
+		}
+
+		colony.maxSingleDeposit = max
+
+		return nil
+	}
+}
+
+// Build the ants' tours with constructor instead of the built-in proportional construction. The pheromone update,
+// best-tour tracking and statistics work as usual on the tours it builds
+func WithConstructor(constructor Constructor) Option {
+	return func(colony *AntColony) error {
+		if constructor == nil {
+			return fmt.Errorf("%w: constructor must not be nil", ErrInvalidParams)
+		}
+
+		colony.constructor = constructor
+
+		return nil
+	}
+}
+
+// Apply a local search to every complete tour the ants construct during the iterations, before it's recorded and
+// deposited, as in hybrid ACO: the ants find good regions and the local search takes their tours to the nearest
+// local optimum. A tour the search makes worse, or that isn't a feasible single visit of every component along the
+// graph's edges, is discarded in favour of the ant's own. TwoOptSearch is a LocalSearch for symmetric TSPs
+func WithLocalSearch(search LocalSearch) Option {
+	return func(colony *AntColony) error {
+		if search == nil {
+			return fmt.Errorf("%w: local search must not be nil", ErrInvalidParams)
+		}
+
+		colony.localSearch = search
+
+		return nil
+	}
+}
+
+// Set the tolerance within which two costs are considered equal, relative to their magnitude (or absolute, for costs
+// below 1); the default is 1e-9. It applies wherever the colony decides whether a tour is better than another: a new
+// best-so-far (from the ants, an initial tour, a migrant or FinalizeSolution) must improve on the old one (or on
+// the baseline) by more than the tolerance, tours within it are ties for the tie-breaking policy, and an iteration
+// only resets the stagnation count if it improved on the best-so-far by more than it. 0 compares costs exactly
+func WithEpsilon(epsilon float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(epsilon) || math.IsInf(epsilon, 0) || epsilon < 0 {
+			return fmt.Errorf("%w: epsilon must be non-negative and finite, got %f", ErrInvalidParams, epsilon)
+		}
+
+		colony.epsilon = epsilon
+
+		return nil
+	}
+}
+
+// Choose with the pseudorandom proportional rule of Ant Colony System: at every step, with probability q0 the ant
+// takes the candidate with the highest score tau^alpha * eta^beta outright, and otherwise it chooses with the
+// selection method as usual. Higher q0 exploits the learned trails more; 0 (the default) disables the rule
+func WithPseudoRandomProportional(q0 float64) Option {
+	return func(colony *AntColony) error {
+		if !(q0 >= 0 && q0 <= 1) {
+			return fmt.Errorf("%w: q0 must be within [0, 1], got %v", ErrInvalidParams, q0)
+		}
+
+		colony.q0 = q0
+
+		return nil
+	}
+}
+
+// Construct the ants' tours on a pool of n goroutines, which speeds up iterations with many ants on large
+// instances; the pheromone update still runs once every ant is done. Each ant draws from its own random source,
+// seeded from the colony's, so a seeded run gives the same results for any n > 1, although they differ from
+// those of a sequential run (n = 1, the default). The Feasible hook of a ConstrainedProblem and a custom Constructor are called concurrently,
+// so they must be safe for concurrent use. Can't be combined with perception noise or a local pheromone update
+func WithWorkers(n int) Option {
+	return func(colony *AntColony) error {
+		if n < 1 {
+			return fmt.Errorf("%w: number of workers must be at least 1, got %d", ErrInvalidParams, n)
+		}
+
+		colony.workers = n
+
+		return nil
+	}
+}
+
+// Use the hyper-cube framework, which keeps every trail within [0, 1] so that parameters tuned on one instance transfer
+// to instances with very different edge-cost scales. The initial pheromones are divided by the largest of them,
+// evaporation works as usual, and each ant deposits rho * (1 / C) / S instead of 1 / C, where S is the sum of 1 / C
+// over all the tours deposited in the iteration: the deposits on an edge add up to at most rho, so a trail moves
+// towards the fraction of the iteration's quality that took it. Only the ants' own deposits are normalized; the
+// extra deposits of strategies such as ElitistStrategy can leave the [0, 1] range
+func WithHyperCube(enabled bool) Option {
+	return func(colony *AntColony) error {
+		colony.hyperCube = enabled
+
+		return nil
+	}
+}
+
+// Apply the local pheromone update of Ant Colony System: whenever an ant takes an edge during an iteration, the
+// edge's pheromone decays towards its initial value tau_0 as tau = (1 - xi) * tau + xi * tau_0. This makes the
+// edges the ants already took less attractive to the ants after them, diversifying the tours of an iteration.
+// Sampling tours (Sample, ExpectedTourLength, ...) doesn't apply it. xi must be in (0, 1]
+func WithLocalPheromoneUpdate(xi float64) Option {
+	return func(colony *AntColony) error {
+		if !(xi > 0 && xi <= 1) {
+			return fmt.Errorf("%w: local pheromone decay must be within (0, 1], got %v", ErrInvalidParams, xi)
+		}
+
+		colony.localDecay = xi
+
+		return nil
+	}
+}
+
+// Record a copy of the best-so-far tour every everyK iterations, e.g. to animate how the solution improved over
+// the run. The frames are returned by BestTourFrames. Each frame holds a full copy of a tour, so a run of I
+// iterations over n components keeps about I / everyK * n edges in memory; raise everyK for long runs
+func WithBestTourFrames(everyK int) Option {
+	return func(colony *AntColony) error {
+		if everyK < 1 {
+			return fmt.Errorf("%w: frame interval must be at least 1, got %d", ErrInvalidParams, everyK)
+		}
+
+		colony.frameInterval = everyK
+
+		return nil
+	}
+}
+
+// Give each ant a memory of its own last complete tour, and multiply the score of every candidate edge in it by
+// 1 + weight, so ants tend to reuse what worked for them individually on top of the colony's shared pheromones.
+// The memory persists across ResetSolution, which only clears the state of the tour being constructed, and is
+// replaced whenever the ant completes a new tour. A weight of 0 (the default) disables the memory
+func WithAntMemory(weight float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(weight) || math.IsInf(weight, 0) || weight < 0 {
+			return fmt.Errorf("%w: ant memory weight must be non-negative and finite, got %f", ErrInvalidParams, weight)
+		}
+
+		colony.memoryWeight = weight
+
+		return nil
+	}
+}
+
+// Choose the ants' start components from a distribution instead of uniformly, e.g. to have more ants start near a
+// busy depot. probs[i] is the probability of starting at component i; the probabilities must be non-negative and
+// sum to 1 (up to rounding). The starts are drawn from the colony RNG whenever an ant is created or reset. This
+// can't be combined with WithFixedPrefix, and greedy seeds still start from distinct components
+func WithStartDistribution(probs []float64) Option {
+	return func(colony *AntColony) error {
+		n := len(colony.constructionGraph.Nodes)
+
+		if len(probs) != n {
+			return fmt.Errorf("%w: start distribution has %d probabilities, expected %d", ErrInvalidParams, len(probs), n)
+		}
+
+		sum := 0.0
+
+		for i, p := range probs {
+			if math.IsNaN(p) || math.IsInf(p, 0) || p < 0 {
+				return fmt.Errorf("%w: start probability of component %d must be non-negative and finite, got %f", ErrInvalidParams, i, p)
+			}
+
+			sum += p
+		}
+
+		if math.Abs(sum-1) > 1e-6 {
+			return fmt.Errorf("%w: start probabilities must sum to 1, got %f", ErrInvalidParams, sum)
+		}
+
+		colony.startDistribution = make([]float64, n)
+		copy(colony.startDistribution, probs)
+
+		return nil
+	}
+}
+
+// Change the number of ants from iteration to iteration, e.g. starting with a few ants for fast exploration and
+// adding more later. schedule is called before every iteration with the number of iterations run so far (starting
+// at 0), and counts below 1 are treated as 1. When the pool grows, the new ants draw their start components from
+// the colony RNG, so runs stay reproducible under a fixed seed and schedule; when it shrinks, the last ants are dropped.
+// By default the number of ants passed to NewAntColony is used in every iteration
+func WithAntCountSchedule(schedule func(iter int) int) Option {
+	return func(colony *AntColony) error {
+		if schedule == nil {
+			return fmt.Errorf("%w: ant count schedule must not be nil", ErrInvalidParams)
+		}
+
+		colony.antSchedule = schedule
+
+		return nil
+	}
+}
+
+// Decay beta as each ant's tour grows, so the heuristic matters less in the final steps. Near the end of a tour few
+// choices remain, and a greedy ant can be trapped into a bad closing edge; a lower beta lets the pheromones, which
+// reflect whole tours, steer it instead. The effective beta after p = len(tour) / n of the tour has been constructed
+// is beta * (1 - (1 - floor) * p), so floor in [0, 1] is the fraction of beta that is left at the end of the tour
+func WithProgressiveBeta(floor float64) Option {
+	return func(colony *AntColony) error {
+		if math.IsNaN(floor) || floor < 0 || floor > 1 {
+			return fmt.Errorf("%w: beta floor must be in [0, 1], got %f", ErrInvalidParams, floor)
+		}
+
+		colony.progressiveBeta = true
+		colony.betaFloor = floor
+
+		return nil
+	}
+}
+
+// Experimental: precompute the scores of all the edges in parallel at the start of every iteration, instead of
+// computing them at every construction step. This speeds up the construction of very large instances, and gives
+// exactly the same tours. It can't be combined with perception noise or progressive beta, whose scores change
+// during construction, and custom strategies must not change the pheromones while the ants construct their tours
+func WithBatchedScoring(batched bool) Option {
+	return func(colony *AntColony) error {
+		colony.batchedScoring = batched
+
+		return nil
+	}
+}
+
+// Ignore the pheromones during construction, so that the ants choose based on the heuristics alone.
+// This is useful for ablation studies measuring the heuristic's standalone contribution.
+// The pheromones are still updated, they just don't affect the choices
+func WithPheromoneDisabled(disabled bool) Option {
+	return func(colony *AntColony) error {
+		colony.pheromoneDisabled = disabled
+
+		return nil
+	}
+}
+
+// Allow ants to visit some components more than once, e.g. depots or transfer hubs that routes return to.
+// capacities maps a component to the maximum number of times an ant may visit it (including the visit at the
+// start of the tour, if it starts there); every other component is visited exactly once.
+// Tours with revisits have more edges than components, so they aren't Hamiltonian cycles
+func WithVisitCapacity(capacities map[uint]int) Option {
+	return func(colony *AntColony) error {
+		colony.capacities = make(map[uint]int)
+
+		for component, capacity := range capacities {
+			if component >= uint(len(colony.constructionGraph.Nodes)) {
+				return fmt.Errorf("%w: visit capacity given for component %d, which is out of range", ErrInvalidParams, component)
+			}
+
+			if capacity < 1 {
+				return fmt.Errorf("%w: visit capacity of component %d must be at least 1, got %d", ErrInvalidParams, component, capacity)
+			}
+
+			colony.capacities[component] = capacity
+		}
+
+		return nil
+	}
+}
+
+// Compute the construction scores in log-space for numerical stability. With a large beta or heuristics
+// spanning many orders of magnitude, tau^alpha * eta^beta can overflow to +Inf or underflow to 0 before it is
+// normalized, breaking the selection; log-space avoids this at the cost of a few more logarithms per step
+func WithLogSpaceScores(logSpace bool) Option {
+	return func(colony *AntColony) error {
+		colony.logSpace = logSpace
+
+		return nil
+	}
+}
+
+// Call callback with a copy of the best tour so far and its cost once the given numbers of iterations have been run,
+// e.g. at 10, 50 and 100 iterations, to study how the solution quality evolves. Iterations are counted over the
+// colony's lifetime (see IterationsRun), starting at 1; checkpoints the run never reaches are simply never called
+func WithCheckpoints(iters []int, callback func(iter int, tour []Edge, cost float64)) Option {
+	return func(colony *AntColony) error {
+		if callback == nil {
+			return fmt.Errorf("%w: checkpoint callback must not be nil", ErrInvalidParams)
+		}
+
+		colony.checkpoints = make(map[int]bool)
+
+		for _, iter := range iters {
+			if iter < 1 {
+				return fmt.Errorf("%w: checkpoint iteration must be at least 1, got %d", ErrInvalidParams, iter)
+			}
+
+			colony.checkpoints[iter] = true
+		}
+
+		colony.onCheckpoint = callback
+
+		return nil
+	}
+}
+
+// Initialize the pheromones from the final pheromones of another colony, e.g. one that solved a similar instance
+// the day before, instead of starting from scratch. This assumes that node indices are stable across the two problems:
+// index i must refer to the same location (city, customer, ...) in both. Only the entries that exist in both matrices
+// are transferred, so the instances may have different sizes; all other entries, as well as transferred entries that
+// aren't finite and positive, keep the values from InitPheromones. The transferred matrix is also what Reset restores
+func WithTransferFrom(source *AntColony) Option {
+	return func(colony *AntColony) error {
+		if source == nil {
+			return fmt.Errorf("%w: transfer source must not be nil", ErrInvalidParams)
+		}
+
+		colony.transferSource = source
+
+		return nil
+	}
+}
+
+// Adjust rho and beta automatically after every iteration according to the diversity of the pheromones,
+// so they don't have to be tuned by hand. See AdaptiveControl for the control law, and ParameterTrajectory
+// for the parameters it chose
+func WithAdaptiveControl(control AdaptiveControl) Option {
+	return func(colony *AntColony) error {
+		if err := control.validate(); err != nil {
+			return err
+		}
+
+		colony.adaptive = &adaptiveState{control: control}
+
+		return nil
+	}
+}
+
+// Make the ants' perception of the pheromones noisy: at every step, each candidate's pheromone is read with
+// probability p, and otherwise replaced by its initial value tau_0. This injects controlled exploration before the
+// sampling step: strong trails are occasionally overlooked, so the ants try alternatives that the heuristic favors,
+// while the colony still follows the trails most of the time. p = 1 (the default) disables the noise, and p = 0
+// makes the ants ignore the learned trails entirely
+func WithPerceptionNoise(p float64) Option {
+	return func(colony *AntColony) error {
+		if !(p >= 0 && p <= 1) {
+			return fmt.Errorf("%w: perception probability must be within [0, 1], got %v", ErrInvalidParams, p)
+		}
+
+		colony.perception = p
+
+		return nil
+	}
+}
+
+// After every pheromone update, rescale the pheromones on the edges leaving each node (excluding self-loops)
+// so they sum to target. This keeps each node's relative preferences but bounds the absolute pheromone levels,
+// as an alternative to min/max bounds for controlling saturation. With standard evaporation alone, the total
+// pheromone drifts towards the equilibrium between evaporation and deposits; with row normalization, evaporation
+// no longer changes the levels by itself (it scales a row uniformly), so only the relative size of the deposits
+// within a row matters, and each update effectively shifts probability mass towards the reinforced edges
+func WithRowNormalization(target float64) Option {
+	return func(colony *AntColony) error {
+		if !(target > 0) || math.IsInf(target, 0) {
+			return fmt.Errorf("%w: row normalization target must be finite and positive, got %v", ErrInvalidParams, target)
+		}
+
+		colony.rowTarget = target
+
+		return nil
+	}
+}
+
+// Seed the first iteration with a diverse set of greedy (nearest-neighbour) tours from distinct starts
+// instead of random walks. The seeds deposit pheromones like constructed tours and initialize the best-so-far
+func WithGreedySeeds(seeds bool) Option {
+	return func(colony *AntColony) error {
+		colony.greedySeeds = seeds
+
+		return nil
+	}
+}
+
+// Choose how ants select the next component (Roulette by default)
+func WithSelection(method SelectionMethod) Option {
+	return func(colony *AntColony) error {
+		if method != Roulette && method != Tournament && method != RankProportional {
+			return fmt.Errorf("%w: unknown selection method %d", ErrInvalidParams, method)
+		}
+
+		colony.selection = method
+
+		return nil
+	}
+}
+
+// Set the number of candidates drawn in each tournament when using Tournament selection
+func WithTournamentSize(size int) Option {
+	return func(colony *AntColony) error {
+		if size < 1 {
+			return fmt.Errorf("%w: tournament size must be at least 1, got %d", ErrInvalidParams, size)
+		}
+
+		colony.tournamentSize = size
+
+		return nil
+	}
+}
+
+// Choose what happens when a tour ties the best-so-far cost (TieKeepFirst by default).
+// With TiePreferDiverse, BestSolution may report a different (but equally good) tour than with TieKeepFirst;
+// with TieKeepAll, BestSolution is unchanged and the other optima are available with BestSolutions
+func WithTieBreak(policy TieBreakPolicy) Option {
+	return func(colony *AntColony) error {
+		if policy != TieKeepFirst && policy != TiePreferDiverse && policy != TieKeepAll {
+			return fmt.Errorf("%w: unknown tie-breaking policy %d", ErrInvalidParams, policy)
+		}
+
+		colony.tieBreak = policy
+
+		return nil
+	}
+}
@@ -0,0 +1,162 @@
+package antcolony
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestOptionsRejectInvalidValues(t *testing.T) {
+	weights := ringWeights(t, 5)
+	tests := []struct {
+		name   string
+		option Option
+	}{
+		{"zero tour budget", WithTotalTourBudget(0)},
+		{"negative tour budget", WithTotalTourBudget(-10)},
+		{"no ants", WithAnts(0)},
+		{"nil ant count schedule", WithAntCountSchedule(nil)},
+		{"zero row normalization target", WithRowNormalization(0)},
+		{"infinite row normalization target", WithRowNormalization(math.Inf(1))},
+		{"negative ant memory", WithAntMemory(-1)},
+		{"no tours per ant", WithToursPerAnt(0, false)},
+		{"zero max single deposit", WithMaxSingleDeposit(0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewAntColony(NewTSPProblem(weights), test.option); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("got error %v, expected %v", err, ErrInvalidParams)
+			}
+		})
+	}
+}
+
+func TestTotalTourBudget(t *testing.T) {
+	weights := ringWeights(t, 5)
+	colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(4), WithTotalTourBudget(10))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(100)
+
+	// The last iteration always completes, so the budget is rounded up to whole iterations
+	if got := colony.ToursConstructed(); got != 12 {
+		t.Errorf("got %d tours, expected 12", got)
+	}
+}
+
+func TestRowNormalizationHitsTheTarget(t *testing.T) {
+	weights := randomSymmetricWeights(rand.New(rand.NewSource(1)), 8)
+
+	for _, target := range []float64{0.5, 1, 20} {
+		colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithRowNormalization(target))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for iter := 0; iter < 5; iter++ {
+			colony.RunSimulation(1)
+
+			for i, edges := range colony.constructionGraph.Edges {
+				sum := 0.0
+
+				for _, edge := range edges {
+					if edge.A != edge.B {
+						sum += colony.Pheromones[edge.A][edge.B]
+					}
+				}
+
+				if math.Abs(sum-target) > 1e-9*target {
+					t.Errorf("target %v, iteration %d: pheromones leaving %d sum to %v", target, iter, i, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestToursPerAnt(t *testing.T) {
+	const ants, iters = 3, 4
+	tests := []struct {
+		k         int
+		bestOnly  bool
+		deposited int
+	}{
+		{1, false, ants * iters},
+		{2, false, 2 * ants * iters},
+		{5, false, 5 * ants * iters},
+		{5, true, ants * iters},
+	}
+
+	for _, test := range tests {
+		strategy := &countingStrategy{}
+		colony, err := NewAntColony(NewTSPProblem(ringWeights(t, 6)), WithSeed(1), WithAnts(ants),
+			WithToursPerAnt(test.k, test.bestOnly), WithPheromoneStrategy(strategy))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		colony.RunSimulation(iters)
+
+		if got := colony.ToursConstructed(); got != ants*test.k*iters {
+			t.Errorf("k = %d, bestOnly = %v: got %d tours, expected %d", test.k, test.bestOnly, got, ants*test.k*iters)
+		}
+
+		if strategy.ants != test.deposited {
+			t.Errorf("k = %d, bestOnly = %v: %d tours were deposited, expected %d", test.k, test.bestOnly, strategy.ants, test.deposited)
+		}
+	}
+}
+
+func TestBaselineBestIsNeverDepositedOn(t *testing.T) {
+	weights := ringWeights(t, 6)
+	baseline := []Edge{{A: 0, B: 2}, {A: 2, B: 4}, {A: 4, B: 1}, {A: 1, B: 3}, {A: 3, B: 5}, {A: 5, B: 0}}
+	tests := []struct {
+		name string
+		cost float64
+		// The strategy of the colony without the baseline that should leave the same trails
+		reference PheromoneStrategy
+	}{
+		// Every tour beats the baseline, so it changes nothing
+		{"beaten", 1e9, ElitistStrategy{}},
+		// No tour beats the baseline, so there is no best-so-far tour and elitism deposits nothing extra
+		{"unbeaten", 1, AntCycleStrategy{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			colony, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(4),
+				WithPheromoneStrategy(ElitistStrategy{}), WithBaselineBest(baseline, test.cost))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			reference, err := NewAntColony(NewTSPProblem(weights), WithSeed(1), WithAnts(4), WithPheromoneStrategy(test.reference))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			colony.RunSimulation(5)
+			reference.RunSimulation(5)
+
+			if !reflect.DeepEqual(colony.Pheromones, reference.Pheromones) {
+				t.Errorf("got trails %v, expected %v", colony.Pheromones, reference.Pheromones)
+			}
+
+			_, cost := colony.BestSolution()
+			_, referenceCost := reference.BestSolution()
+
+			if expected := math.Min(test.cost, referenceCost); cost != expected {
+				t.Errorf("got best cost %v, expected %v", cost, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package antcolony
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Have the ants construct their tours for a round of the iteration on a pool of goroutines. Returns which ants
+// constructed a tour: once the iteration timeout has passed or the run is cancelled, the ants that haven't started yet don't (except the
+// first ant of the iteration, so the run progresses). The ants are reset and seeded before any of them starts, in
+// their order and from the colony RNG, so each ant's tour only depends on its own random source and not on how
+// the ants are scheduled
+func (colony *AntColony) constructConcurrently(round int, start time.Time) []bool {
+	built := make([]bool, len(colony.ants))
+
+	for i := range colony.ants {
+		ant := &colony.ants[i]
+
+		if round > 0 {
+			ant.ResetSolution(colony)
+		}
+
+		seed := colony.rng.Int63()
+
+		if ant.rng == nil {
+			ant.rng = rand.New(rand.NewSource(seed))
+		} else {
+			ant.rng.Seed(seed)
+		}
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < colony.workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range next {
+				if (round > 0 || i > 0) && colony.stopConstructing(start) {
+					continue
+				}
+
+				colony.construct(&colony.ants[i])
+				built[i] = true
+			}
+		}()
+	}
+
+	for i := range colony.ants {
+		next <- i
+	}
+
+	close(next)
+	wg.Wait()
+
+	return built
+}
@@ -0,0 +1,79 @@
+package antcolony
+
+import "math"
+
+// Count the iterations in a row in which the best-so-far tour didn't improve, and restart the search once the
+// colony has stagnated for as long as set with WithRestartOnStagnation. previousBest is the best-so-far cost
+// before the iteration
+func (colony *AntColony) checkStagnation(previousBest float64) {
+	if colony.improves(colony.bestCost, previousBest) {
+		colony.stagnation = 0
+	} else {
+		colony.stagnation++
+	}
+
+	if colony.restartAfter > 0 && colony.stagnation >= colony.restartAfter {
+		colony.restart()
+	}
+}
+
+// Count the iterations in a row in which the best tour of the whole run didn't improve. previousOverall is its cost
+// before the iteration
+func (colony *AntColony) checkImprovement(previousOverall float64) {
+	if _, overall := colony.overallBest(); colony.improves(overall, previousOverall) {
+		colony.unimproved = 0
+	} else {
+		colony.unimproved++
+	}
+}
+
+// Whether the best tour of the whole run hasn't improved for as many iterations in a row as set with
+// WithEarlyStopping. Once it has converged, the colony doesn't run any more iterations until it's Reset
+func (colony *AntColony) Converged() bool {
+	return colony.stopAfter > 0 && colony.unimproved >= colony.stopAfter
+}
+
+// Restart the search: the pheromones are reset to their initial values, so the colony explores afresh, and the best
+// tour of the run is reinforced if a restart bias is set. Unless the best-so-far is kept, the search forgets it, but
+// it's put aside so BestSolution still reports the overall best
+func (colony *AntColony) restart() {
+	colony.Pheromones = copyMatrix(colony.initialPheromones)
+	colony.stagnation = 0
+	colony.restarts++
+
+	if best, _ := colony.overallBest(); colony.restartBias > 0 && best != nil {
+		colony.DepositTour(best, colony.restartBias*colony.meanInitialPheromone())
+	}
+
+	if colony.restartKeepBest {
+		return
+	}
+
+	if colony.improves(colony.bestCost, colony.overallCost) {
+		colony.overallTour = colony.bestTour
+		colony.overallCost = colony.bestCost
+		colony.overallIteration = colony.bestIteration
+		colony.overallTime = colony.timeToBest
+	}
+
+	colony.bestTour = nil
+	colony.bestCost = math.Inf(1)
+	colony.bestConstruction = nil
+	colony.optima = nil
+	colony.recentBests = nil
+}
+
+// The best tour of the whole run and its cost, whether or not the search still remembers it. The tour
+// isn't copied
+func (colony *AntColony) overallBest() ([]Edge, float64) {
+	if colony.improves(colony.overallCost, colony.bestCost) {
+		return colony.overallTour, colony.overallCost
+	}
+
+	return colony.bestTour, colony.bestCost
+}
+
+// The number of times the colony restarted because it stagnated
+func (colony *AntColony) Restarts() int {
+	return colony.restarts
+}
@@ -0,0 +1,96 @@
+package antcolony
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+// Records every value drawn from the colony's random source, so a run can be replayed exactly with WithRNGReplay
+type RNGRecorder struct {
+	source rand.Source
+	stream []int64
+}
+
+func (recorder *RNGRecorder) Int63() int64 {
+	value := recorder.source.Int63()
+	recorder.stream = append(recorder.stream, value)
+
+	return value
+}
+
+func (recorder *RNGRecorder) Seed(seed int64) {
+	recorder.source.Seed(seed)
+}
+
+// The values drawn so far, in order
+func (recorder *RNGRecorder) Stream() []int64 {
+	stream := make([]int64, len(recorder.stream))
+	copy(stream, recorder.stream)
+
+	return stream
+}
+
+// A random source that returns the values of a recorded stream, then falls back to another source
+type replaySource struct {
+	stream   []int64
+	pos      int
+	fallback rand.Source
+}
+
+func (replay *replaySource) Int63() int64 {
+	if replay.pos < len(replay.stream) {
+		replay.pos++
+		return replay.stream[replay.pos-1]
+	}
+
+	return replay.fallback.Int63()
+}
+
+func (replay *replaySource) Seed(seed int64) {
+	replay.fallback.Seed(seed)
+}
+
+// The random source of the colony, as configured by the options
+func (colony *AntColony) randSource() rand.Source {
+	source := colony.source
+
+	if source == nil {
+		source = rand.NewSource(colony.seed)
+	}
+
+	if colony.replay != nil {
+		source = &replaySource{stream: colony.replay, fallback: source}
+	}
+
+	if colony.recorder != nil {
+		colony.recorder.source = source
+		source = colony.recorder
+	}
+
+	return source
+}
+
+// Derive a seed from the content of an instance, so that runs on the same instance are reproducible without
+// agreeing on a seed. The seed is an FNV-1a hash of the matrix dimensions and the bits of every weight, so any
+// change to the instance gives an unrelated seed
+func SeedFromInstance(weights [][]float64) int64 {
+	hash := fnv.New64a()
+	buf := make([]byte, 8)
+
+	binary.LittleEndian.PutUint64(buf, uint64(len(weights)))
+	hash.Write(buf)
+
+	for _, row := range weights {
+		binary.LittleEndian.PutUint64(buf, uint64(len(row)))
+		hash.Write(buf)
+
+		for _, w := range row {
+			binary.LittleEndian.PutUint64(buf, math.Float64bits(w))
+			hash.Write(buf)
+		}
+	}
+
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,55 @@
+package antcolony
+
+import "sort"
+
+// A tour and its cost
+type TourResult struct {
+	Tour []Edge
+	Cost float64
+}
+
+// Sample n tours from the current pheromones and heuristics, e.g. to deploy a colony whose pheromones were trained
+// on representative instances. This is inference only: the trails don't change (there is no evaporation or
+// deposit), and the best-so-far tour isn't updated, so it's cheaper per tour than an iteration. Returns the complete
+// tours sorted by cost, so the first one is the best; tours whose ants got stuck are left out
+func (colony *AntColony) Sample(n int) []TourResult {
+	// The pheromones are static, so the scores can be computed once for all the samples
+	if colony.batchedScoring {
+		colony.buildScoreTable()
+		defer func() { colony.scoresBatched = false }()
+	}
+
+	results := make([]TourResult, 0, n)
+
+	for i := 0; i < n; i++ {
+		ant := colony.newAnt()
+		colony.construct(&ant)
+
+		if colony.IsComplete(&ant) {
+			results = append(results, TourResult{Tour: ant.tour, Cost: colony.tourCost(ant.tour)})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Cost < results[j].Cost })
+
+	return results
+}
+
+// Squeeze a little more out of a finished run: sample tours from the converged pheromones and make the best of
+// them the best-so-far if it improves on it. If samples isn't positive, as many tours as the colony has ants
+// are sampled, i.e. one iteration's worth of construction without the update. Like Sample, this leaves the trails
+// unchanged. Returns the best tour of the whole run and its cost, as BestSolution does afterwards
+func (colony *AntColony) FinalizeSolution(samples int) ([]Edge, float64) {
+	if samples <= 0 {
+		samples = len(colony.ants)
+	}
+
+	results := colony.Sample(samples)
+
+	if len(results) > 0 && colony.improves(results[0].Cost, colony.costToBeat()) {
+		colony.setBest(results[0].Tour, results[0].Cost)
+		colony.optima = [][]Edge{colony.bestTour}
+	}
+
+	return colony.BestSolution()
+}
@@ -0,0 +1,142 @@
+package antcolony
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// How an ant chooses the next component among the feasible candidates
+type SelectionMethod int
+
+const (
+	// Choose each candidate with probability proportional to its score (roulette wheel). This is the default
+	Roulette SelectionMethod = iota
+	// Draw a few candidates uniformly at random and choose the one with the highest score.
+	// Larger tournaments are greedier, while a tournament of size 1 is a uniformly random choice
+	Tournament
+	// Choose each candidate with probability proportional to its rank: with k candidates, the best one has weight k,
+	// the second best k - 1, and so on down to 1 for the worst. Only the order of the scores matters, so this is
+	// insensitive to their absolute magnitude
+	RankProportional
+)
+
+// The default number of candidates drawn in each tournament
+const defaultTournamentSize = 2
+
+// Choose the next component among the candidates, given their scores, drawing from rng
+func (colony *AntColony) selectNext(rng *rand.Rand, candidates []uint, scores []float64) uint {
+	// With the pseudorandom proportional rule, the ant sometimes exploits the best candidate outright
+	if colony.q0 > 0 && rng.Float64() < colony.q0 {
+		return candidates[argmax(scores)]
+	}
+
+	switch colony.selection {
+	case Tournament:
+		return tournamentSelection(rng, candidates, scores, colony.tournamentSize)
+	case RankProportional:
+		return rankSelection(rng, candidates, scores)
+	default:
+		return weightedSampling(rng, candidates, rouletteProbabilities(scores))
+	}
+}
+
+// The index of the highest score (the first one, on ties)
+func argmax(scores []float64) int {
+	best := 0
+
+	for i := range scores {
+		if scores[i] > scores[best] {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// Normalize the scores to convert into a valid probability distribution
+func rouletteProbabilities(scores []float64) []float64 {
+	denom := 0.0
+
+	for _, score := range scores {
+		denom += score
+	}
+
+	probs := make([]float64, len(scores))
+
+	for i, score := range scores {
+		// If all the scores vanished, we fall back to a uniform distribution
+		if denom > 0 {
+			probs[i] = score / denom
+		} else {
+			probs[i] = 1.0 / float64(len(scores))
+		}
+	}
+
+	return probs
+}
+
+// Sample from a discrete distribution where the probability of sampling v_i is p_i: P(v_i) = p_i
+func weightedSampling(rng *rand.Rand, values []uint, probs []float64) uint {
+	// Generate a random number 0 <= x < 1
+	x := rng.Float64()
+	// Track culminative probability
+	culm := 0.0
+	// Due to rounding, the probabilities might sum to slightly less than x.
+	// In that case we return the last value that could have been sampled
+	last := values[len(values)-1]
+
+	for i, value := range values {
+		if probs[i] <= 0 {
+			continue
+		}
+
+		culm += probs[i]
+		last = value
+
+		if x < culm {
+			return value
+		}
+	}
+
+	return last
+}
+
+// Draw size candidates uniformly at random (with replacement) and return the one with the highest score
+func tournamentSelection(rng *rand.Rand, candidates []uint, scores []float64, size int) uint {
+	best := rng.Intn(len(candidates))
+
+	for i := 1; i < size; i++ {
+		contender := rng.Intn(len(candidates))
+
+		if scores[contender] > scores[best] {
+			best = contender
+		}
+	}
+
+	return candidates[best]
+}
+
+// Sample a candidate with probability proportional to its rank among the candidates, ordered by score
+func rankSelection(rng *rand.Rand, candidates []uint, scores []float64) uint {
+	k := len(candidates)
+	ranked := make([]int, k)
+
+	for i := range ranked {
+		ranked[i] = i
+	}
+
+	// Candidates with equal scores keep their relative order, so the result is deterministic
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+
+	values := make([]uint, k)
+	probs := make([]float64, k)
+	// The sum of the weights k + (k - 1) + ... + 1
+	total := float64(k*(k+1)) / 2
+
+	for r, idx := range ranked {
+		values[r] = candidates[idx]
+		probs[r] = float64(k-r) / total
+	}
+
+	return weightedSampling(rng, values, probs)
+}
@@ -0,0 +1,73 @@
+package antcolony
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestTournamentSelectionReturnsACandidate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name       string
+		candidates []uint
+		scores     []float64
+	}{
+		{"single candidate", []uint{7}, []float64{0.5}},
+		{"distinct scores", []uint{3, 1, 4}, []float64{0.2, 0.9, 0.1}},
+		{"vanished scores", []uint{2, 5, 8, 9}, []float64{0, 0, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, size := range []int{1, 2, 5, 20} {
+				for i := 0; i < 100; i++ {
+					if got := tournamentSelection(rng, test.candidates, test.scores, size); !slices.Contains(test.candidates, got) {
+						t.Fatalf("size %d: got %d, which isn't one of the candidates %v", size, got, test.candidates)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTournamentSelectionBuildsFeasibleTours(t *testing.T) {
+	colony, err := NewAntColony(NewTSPProblem(ringWeights(t, 7)), WithSeed(1), WithSelection(Tournament), WithTournamentSize(3))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(5)
+
+	if tour, _ := colony.BestSolution(); ValidateTour(tour, 7) != nil {
+		t.Errorf("got invalid tour %v", tour)
+	}
+}
+
+func TestRankSelectionFavorsBetterRanks(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	candidates := []uint{10, 20, 30, 40}
+	// The scores are far apart, but only their order matters: the best of 4 is drawn with probability 4/10, the
+	// next with 3/10, and so on
+	scores := []float64{0.1, 100, 0.3, 0.01}
+	expected := map[uint]float64{20: 0.4, 30: 0.3, 10: 0.2, 40: 0.1}
+
+	const draws = 20000
+	counts := make(map[uint]int)
+
+	for i := 0; i < draws; i++ {
+		counts[rankSelection(rng, candidates, scores)]++
+	}
+
+	for candidate, prob := range expected {
+		if freq := float64(counts[candidate]) / draws; math.Abs(freq-prob) > 0.02 {
+			t.Errorf("candidate %d was drawn with frequency %v, expected %v", candidate, freq, prob)
+		}
+	}
+
+	if !(counts[20] > counts[30] && counts[30] > counts[10] && counts[10] > counts[40]) {
+		t.Errorf("frequencies %v don't follow the ranks", counts)
+	}
+}
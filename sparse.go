@@ -0,0 +1,111 @@
+package antcolony
+
+import "fmt"
+
+// A problem whose pheromones and heuristics are stored sparsely, with an entry per edge of the construction graph
+// rather than per pair of components, so that large graphs with few edges (e.g. road networks) fit in memory. If a
+// problem implements this, the colony uses InitSparsePheromones and InitSparseHeuristics instead of InitPheromones
+// and InitHeuristics, and keeps their layout: row a holds one entry for every edge in Graph.Edges[a], in the same
+// order. The same goes for the exported Pheromones, for EdgeUsage and for the matrices passed to UpdateHeuristics
+// (or returned by InitHeuristicSet, for a MultiHeuristicProblem). Each edge may only be listed once
+type SparseProblem interface {
+	ACOptimizable
+	// The initial pheromones, with row a aligned with Graph.Edges[a]
+	InitSparsePheromones(num_ants uint) [][]float64
+	// The heuristics, with row a aligned with Graph.Edges[a]
+	InitSparseHeuristics() [][]float64
+}
+
+// Index the edges of a graph for the sparse layout: columns[a][b] is the position of edge (a, b) in Edges[a], and
+// thus of its entry in row a of the colony's matrices. An edge listed twice would have two entries, only one of
+// which the colony could ever find, so it's rejected
+func sparseColumns(graph Graph) ([]map[uint]int, error) {
+	columns := make([]map[uint]int, len(graph.Edges))
+
+	for a, edges := range graph.Edges {
+		columns[a] = make(map[uint]int, len(edges))
+
+		for j, edge := range edges {
+			if _, ok := columns[a][edge.B]; ok {
+				return nil, fmt.Errorf("%w: edge (%d, %d) is listed more than once", ErrInvalidGraph, edge.A, edge.B)
+			}
+
+			columns[a][edge.B] = j
+		}
+	}
+
+	return columns, nil
+}
+
+// The position of an edge's entry in row edge.A of the colony's matrices: edge.B in the dense layout, and the
+// edge's position in Graph.Edges[edge.A] in the sparse one. An edge without an entry in the sparse layout gets -1,
+// so indexing with it panics, just like indexing past the end of a dense row
+func (colony *AntColony) column(edge Edge) int {
+	if colony.columns == nil {
+		return int(edge.B)
+	}
+
+	if j, ok := colony.columns[edge.A][edge.B]; ok {
+		return j
+	}
+
+	return -1
+}
+
+// Whether the colony's matrices have an entry for an edge, and its position in row edge.A if so
+func (colony *AntColony) entry(edge Edge) (int, bool) {
+	if colony.columns == nil {
+		ok := edge.A < uint(len(colony.Pheromones)) && edge.B < uint(len(colony.Pheromones[edge.A]))
+		return int(edge.B), ok
+	}
+
+	if edge.A >= uint(len(colony.columns)) {
+		return -1, false
+	}
+
+	j, ok := colony.columns[edge.A][edge.B]
+
+	return j, ok
+}
+
+// The number of entries in row a of the colony's matrices: n in the dense layout, and the number of edges leaving
+// a in the sparse one
+func (colony *AntColony) rowLength(a int) int {
+	if colony.columns == nil {
+		return len(colony.constructionGraph.Nodes)
+	}
+
+	return len(colony.constructionGraph.Edges[a])
+}
+
+// Check that a matrix has the colony's layout: n x n in the dense layout, since the colony indexes it densely
+// (e.g. when evaporating) and every entry must exist, not just the ones on the graph's edges; and one entry per
+// edge in the sparse layout
+func (colony *AntColony) validateLayout(matrix [][]float64, name string) error {
+	if colony.columns == nil {
+		return validateSquare(matrix, len(colony.constructionGraph.Nodes), name)
+	}
+
+	if len(matrix) != len(colony.constructionGraph.Nodes) {
+		return fmt.Errorf("%w: %s matrix has %d rows, expected %d", ErrRaggedMatrix, name, len(matrix), len(colony.constructionGraph.Nodes))
+	}
+
+	for i, row := range matrix {
+		if len(row) != colony.rowLength(i) {
+			return fmt.Errorf("%w: %s matrix row %d has %d entries, expected one for each of the %d edges", ErrRaggedMatrix, name, i, len(row), colony.rowLength(i))
+		}
+	}
+
+	return nil
+}
+
+// Construct a matrix of zero usage counts in the colony's layout
+func (colony *AntColony) newUsageMatrix() [][]uint {
+	usage := make([][]uint, len(colony.constructionGraph.Nodes))
+
+	for i := range usage {
+		usage[i] = make([]uint, colony.rowLength(i))
+	}
+
+	return usage
+}
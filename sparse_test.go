@@ -0,0 +1,236 @@
+package antcolony
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// A SparseProblem over a fixed graph, with the matrices given in the sparse layout
+type sparseProblem struct {
+	graph      Graph
+	pheromones [][]float64
+	heuristics [][]float64
+}
+
+func (problem *sparseProblem) ConstructGraph() Graph {
+	return problem.graph
+}
+
+func (problem *sparseProblem) InitPheromones(num_ants uint) [][]float64 {
+	return nil
+}
+
+func (problem *sparseProblem) InitHeuristics() [][]float64 {
+	return nil
+}
+
+func (problem *sparseProblem) InitSparsePheromones(num_ants uint) [][]float64 {
+	return copyMatrix(problem.pheromones)
+}
+
+func (problem *sparseProblem) InitSparseHeuristics() [][]float64 {
+	return copyMatrix(problem.heuristics)
+}
+
+// Lay out value(edge) for every edge of a graph, in the sparse layout and in the dense one (where the entries off
+// the graph are 1)
+func sparseAndDense(graph Graph, value func(edge Edge) float64) ([][]float64, [][]float64) {
+	sparse := make([][]float64, len(graph.Edges))
+	dense := filledMatrix(len(graph.Nodes), 1)
+
+	for a, edges := range graph.Edges {
+		for _, edge := range edges {
+			sparse[a] = append(sparse[a], value(edge))
+			dense[edge.A][edge.B] = value(edge)
+		}
+	}
+
+	return sparse, dense
+}
+
+// An undirected ring of 8 with three chords and a self-loop, whose edges are listed in no particular order
+func chordedRing() Graph {
+	const n = 8
+	graph := Graph{Nodes: make([]uint, n), Edges: make([][]Edge, n)}
+	connect := func(a, b uint) {
+		graph.Edges[a] = append(graph.Edges[a], Edge{A: a, B: b})
+		graph.Edges[b] = append(graph.Edges[b], Edge{A: b, B: a})
+	}
+
+	for i := uint(0); i < n; i++ {
+		graph.Nodes[i] = i
+		connect(i, (i+1)%n)
+	}
+
+	connect(0, 4)
+	connect(2, 6)
+	connect(5, 1)
+	graph.Edges[3] = append([]Edge{{A: 3, B: 3}}, graph.Edges[3]...)
+
+	return graph
+}
+
+func TestSparseLayoutMatchesTheDenseOne(t *testing.T) {
+	graph := chordedRing()
+	heuristic := func(edge Edge) float64 {
+		// A huge heuristic on the self-loop, which must be zeroed in both layouts
+		if edge.A == edge.B {
+			return 1e9
+		}
+
+		return 1 / float64(1+(edge.A+edge.B)*7%5)
+	}
+	sparseHeuristics, denseHeuristics := sparseAndDense(graph, heuristic)
+	sparsePheromones, densePheromones := sparseAndDense(graph, func(edge Edge) float64 { return 0.5 })
+
+	tests := []struct {
+		name string
+		opts func() []Option
+	}{
+		{"ant cycle", func() []Option { return nil }},
+		{"candidate lists", func() []Option { return []Option{WithCandidateLists(2)} }},
+		{"batched scoring", func() []Option { return []Option{WithBatchedScoring(true)} }},
+		{"max-min", func() []Option { return []Option{WithPheromoneStrategy(MaxMinStrategy{})} }},
+		{"row normalization", func() []Option { return []Option{WithRowNormalization(1)} }},
+		{"hyper-cube", func() []Option { return []Option{WithHyperCube(true)} }},
+		{"betweenness and greedy seeds", func() []Option { return []Option{WithBetweennessInit(4), WithGreedySeeds(true)} }},
+		{"ant colony system", func() []Option {
+			return []Option{WithPheromoneStrategy(ACSStrategy{}), WithLocalPheromoneUpdate(0.1), WithPseudoRandomProportional(0.9)}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := append([]Option{WithSeed(1), WithAnts(4)}, test.opts()...)
+			sparse, err := NewAntColony(&sparseProblem{graph: graph, pheromones: sparsePheromones, heuristics: sparseHeuristics}, opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dense, err := NewAntColony(&matrixProblem{graph: graph, pheromones: copyMatrix(densePheromones), heuristics: copyMatrix(denseHeuristics)}, opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sparse.RunSimulation(10)
+			dense.RunSimulation(10)
+
+			sparseTour, sparseCost := sparse.BestSolution()
+			denseTour, denseCost := dense.BestSolution()
+
+			if sparseTour == nil || !reflect.DeepEqual(sparseTour, denseTour) || sparseCost != denseCost {
+				t.Fatalf("sparse best %v costing %v, dense best %v costing %v", sparseTour, sparseCost, denseTour, denseCost)
+			}
+
+			sparseUsage, denseUsage := sparse.EdgeUsage(), dense.EdgeUsage()
+
+			for a, edges := range graph.Edges {
+				if len(sparse.Pheromones[a]) != len(edges) || len(sparseUsage[a]) != len(edges) {
+					t.Fatalf("row %d has %d pheromones and %d usage counts for %d edges", a, len(sparse.Pheromones[a]), len(sparseUsage[a]), len(edges))
+				}
+
+				for j, edge := range edges {
+					if sparse.Pheromones[a][j] != dense.Pheromones[edge.A][edge.B] {
+						t.Errorf("pheromone on %v is %v sparse and %v dense", edge, sparse.Pheromones[a][j], dense.Pheromones[edge.A][edge.B])
+					}
+
+					if sparseUsage[a][j] != denseUsage[edge.A][edge.B] {
+						t.Errorf("usage of %v is %v sparse and %v dense", edge, sparseUsage[a][j], denseUsage[edge.A][edge.B])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTransferAcrossLayouts(t *testing.T) {
+	graph := chordedRing()
+	ones, square := sparseAndDense(graph, func(edge Edge) float64 { return 1 })
+	source, err := NewAntColony(&sparseProblem{graph: graph, pheromones: ones, heuristics: ones}, WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source.RunSimulation(5)
+	colony, err := NewAntColony(&matrixProblem{graph: graph, pheromones: copyMatrix(square), heuristics: square}, WithTransferFrom(source))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for a, edges := range graph.Edges {
+		for j, edge := range edges {
+			if tau := colony.Pheromones[edge.A][edge.B]; edge.A != edge.B && tau != source.Pheromones[a][j] {
+				t.Errorf("transferred pheromone on %v is %v, expected %v", edge, tau, source.Pheromones[a][j])
+			}
+		}
+	}
+
+	if _, err := PheromoneDiff(source, colony); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("comparing the layouts gave %v, expected %v", err, ErrInvalidParams)
+	}
+}
+
+func TestSparseProblemValidation(t *testing.T) {
+	graph := chordedRing()
+	heuristics, _ := sparseAndDense(graph, func(edge Edge) float64 { return 1 })
+	pheromones, square := sparseAndDense(graph, func(edge Edge) float64 { return 1 })
+	vanishing, _ := sparseAndDense(graph, func(edge Edge) float64 { return 0 })
+	duplicated := chordedRing()
+	duplicated.Edges[0] = append(duplicated.Edges[0], Edge{A: 0, B: 1})
+	duplicatedHeuristics, _ := sparseAndDense(duplicated, func(edge Edge) float64 { return 1 })
+
+	tests := []struct {
+		name    string
+		problem *sparseProblem
+		err     error
+	}{
+		{"valid", &sparseProblem{graph: graph, pheromones: pheromones, heuristics: heuristics}, nil},
+		{"dense heuristics", &sparseProblem{graph: graph, pheromones: pheromones, heuristics: square}, ErrRaggedMatrix},
+		{"dense pheromones", &sparseProblem{graph: graph, pheromones: square, heuristics: heuristics}, ErrRaggedMatrix},
+		{"missing row", &sparseProblem{graph: graph, pheromones: pheromones[1:], heuristics: heuristics}, ErrRaggedMatrix},
+		{"vanishing pheromones", &sparseProblem{graph: graph, pheromones: vanishing, heuristics: heuristics}, ErrInvalidParams},
+		{"duplicate edge", &sparseProblem{graph: duplicated, pheromones: duplicatedHeuristics, heuristics: duplicatedHeuristics}, ErrInvalidGraph},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewAntColony(test.problem); !errors.Is(err, test.err) {
+				t.Errorf("got %v, expected %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestSparseLayoutScalesToLargeGraphs(t *testing.T) {
+	// Dense matrices would take 2 * 8 * 10^10 bytes here
+	const n = 100000
+	graph := directedRing(n)
+	ones := make([][]float64, n)
+
+	for i := range ones {
+		ones[i] = []float64{1}
+	}
+
+	problem := &sparseProblem{graph: graph, pheromones: ones, heuristics: ones}
+	colony, err := NewAntColony(problem, WithSeed(1), WithAnts(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	colony.RunSimulation(1)
+
+	if tour, cost := colony.BestSolution(); len(tour) != n || cost != n {
+		t.Fatalf("got a tour of %d edges costing %v, expected the whole ring", len(tour), cost)
+	}
+
+	// One iteration of Ant System on a single tour: the trails evaporate by half, then gain 1 / n
+	if tau := colony.Pheromones[n-1][0]; tau != 0.5+1.0/n {
+		t.Errorf("got pheromone %v on the closing edge, expected %v", tau, 0.5+1.0/n)
+	}
+}
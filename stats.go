@@ -0,0 +1,198 @@
+package antcolony
+
+import "math"
+
+// Statistics about the tours constructed in a single iteration. The gap between the mean and the best cost is a
+// signal of the colony's diversity: it shrinks as the colony converges
+type IterationStats struct {
+	// The number of iterations run so far, including this one
+	Iteration int
+	// How many of the ants completed their tours in this iteration
+	Completed int
+	// The best, mean and worst cost of the tours completed in this iteration. If none were completed,
+	// the best cost is +Inf, the worst is -Inf and the mean is NaN
+	BestCost  float64
+	MeanCost  float64
+	WorstCost float64
+	// The cost of the best tour found so far in the run
+	BestSoFar float64
+	// How exploitative the ants' choices were: the probability of the most attractive candidate, averaged over
+	// every construction step with more than one candidate. It ranges from 1/k with k candidates (a uniform choice)
+	// to 1 (a deterministic choice), so it rises as alpha, beta and the pheromone differences grow. The probabilities
+	// are the normalized scores, as used by Roulette selection. NaN if no ant had a choice to make, e.g. with a
+	// custom Constructor
+	Exploitation float64
+	// The smallest, mean and largest pheromone on the edges of the graph (other than self-loops) after this
+	// iteration's update. All three are NaN for a graph without such edges
+	MinPheromone  float64
+	MeanPheromone float64
+	MaxPheromone  float64
+	// The lambda-branching factor of the pheromones after this iteration's update, with lambda = 0.05 (see
+	// BranchingFactor). It falls towards 1 (or 2 with symmetric trails) as the colony converges
+	BranchingFactor float64
+}
+
+// The lambda used for the branching factor reported in IterationStats
+const statsBranchingLambda = 0.05
+
+// Observes the colony at the end of every iteration, e.g. to log its progress or to adjust its parameters.
+// Observers are called after the callback set with WithIterationCallback, in the order they were added
+type IterationObserver interface {
+	ObserveIteration(colony *AntColony, stats IterationStats)
+}
+
+// Accumulates the costs of the tours completed in an iteration
+type iterationCosts struct {
+	completed int
+	sum       float64
+	best      float64
+	worst     float64
+}
+
+func newIterationCosts() iterationCosts {
+	return iterationCosts{best: math.Inf(1), worst: math.Inf(-1)}
+}
+
+func (costs *iterationCosts) add(cost float64) {
+	costs.completed++
+	costs.sum += cost
+	costs.best = math.Min(costs.best, cost)
+	costs.worst = math.Max(costs.worst, cost)
+}
+
+// Add a construction step to the ant's exploitation measure, which the colony collects once the ant's tour is
+// recorded. Forced moves aren't choices, so they're left out
+func (ant *Ant) recordChoice(scores []float64) {
+	if len(scores) < 2 {
+		return
+	}
+
+	top := 0.0
+
+	for _, p := range rouletteProbabilities(scores) {
+		top = math.Max(top, p)
+	}
+
+	ant.topMass += top
+	ant.choices++
+}
+
+func (costs *iterationCosts) mean() float64 {
+	if costs.completed == 0 {
+		return math.NaN()
+	}
+
+	return costs.sum / float64(costs.completed)
+}
+
+// Record the statistics of the iteration that just finished, and report them to the callback if one is set
+func (colony *AntColony) recordStats(costs iterationCosts) {
+	_, bestSoFar := colony.overallBest()
+	stats := IterationStats{
+		Iteration:    colony.iterations,
+		Completed:    costs.completed,
+		BestCost:     costs.best,
+		MeanCost:     costs.mean(),
+		WorstCost:    costs.worst,
+		BestSoFar:    bestSoFar,
+		Exploitation: math.NaN(),
+	}
+
+	if colony.exploitationSteps > 0 {
+		stats.Exploitation = colony.exploitationSum / float64(colony.exploitationSteps)
+	}
+
+	stats.MinPheromone, stats.MeanPheromone, stats.MaxPheromone = colony.pheromoneStats()
+	stats.BranchingFactor = colony.BranchingFactor(statsBranchingLambda)
+	colony.meanCosts = append(colony.meanCosts, stats.MeanCost)
+	colony.branchingFactors = append(colony.branchingFactors, stats.BranchingFactor)
+
+	if colony.onIteration != nil {
+		colony.onIteration(stats)
+	}
+
+	for _, observer := range colony.observers {
+		observer.ObserveIteration(colony, stats)
+	}
+}
+
+// The smallest, mean and largest pheromone on the edges of the graph other than self-loops
+func (colony *AntColony) pheromoneStats() (float64, float64, float64) {
+	tauMin := math.Inf(1)
+	tauMax := math.Inf(-1)
+	sum := 0.0
+	count := 0
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A == edge.B {
+				continue
+			}
+
+			tau := colony.Pheromones[edge.A][colony.column(edge)]
+			tauMin = math.Min(tauMin, tau)
+			tauMax = math.Max(tauMax, tau)
+			sum += tau
+			count++
+		}
+	}
+
+	if count == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	return tauMin, sum / float64(count), tauMax
+}
+
+// The mean cost of the tours completed in each iteration run so far (NaN for iterations in which no tour was
+// completed). Together with the best and worst tours, this shows how the colony converges
+func (colony *AntColony) MeanCostHistory() []float64 {
+	history := make([]float64, len(colony.meanCosts))
+	copy(history, colony.meanCosts)
+
+	return history
+}
+
+// The lambda-branching factor of the pheromones after each iteration run so far, as reported in IterationStats.
+// A plateau close to its minimum means the trails have converged, and further iterations mostly repeat the same tours
+func (colony *AntColony) BranchingFactorHistory() []float64 {
+	history := make([]float64, len(colony.branchingFactors))
+	copy(history, colony.branchingFactors)
+
+	return history
+}
+
+// A snapshot of the best-so-far tour, as recorded by WithBestTourFrames
+type TourFrame struct {
+	// The number of iterations run when the snapshot was taken
+	Iteration int
+	Tour      []Edge
+	Cost      float64
+}
+
+// Record a frame of the best-so-far tour if this is one of the iterations to record. Iterations before any
+// tour was completed have no frame
+func (colony *AntColony) recordFrame() {
+	best, cost := colony.overallBest()
+
+	if colony.frameInterval == 0 || colony.iterations%colony.frameInterval != 0 || best == nil {
+		return
+	}
+
+	tour := make([]Edge, len(best))
+	copy(tour, best)
+	colony.frames = append(colony.frames, TourFrame{Iteration: colony.iterations, Tour: tour, Cost: cost})
+}
+
+// The frames recorded so far with WithBestTourFrames, in order. Each frame has its own copy of the tour
+func (colony *AntColony) BestTourFrames() []TourFrame {
+	frames := make([]TourFrame, len(colony.frames))
+
+	for i, frame := range colony.frames {
+		frames[i] = frame
+		frames[i].Tour = make([]Edge, len(frame.Tour))
+		copy(frames[i].Tour, frame.Tour)
+	}
+
+	return frames
+}
@@ -0,0 +1,451 @@
+package antcolony
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A PheromoneStrategy decides how the pheromones are updated at the end of every iteration,
+// once all the ants have constructed their tours. Evaporate is called first, then Deposit.
+// Custom strategies can read the ants' tours with Ant.Tour, score them with AntColony.TourCost,
+// and reinforce them with AntColony.DepositTour (or modify the exported Pheromones matrix directly)
+type PheromoneStrategy interface {
+	// Evaporate some of the pheromones on the edges
+	Evaporate(colony *AntColony)
+	// Deposit new pheromones based on the tours constructed by the ants in this iteration
+	Deposit(colony *AntColony, ants []Ant)
+}
+
+// The classic Ant System (ant-cycle) update: all the pheromones evaporate at the same rate,
+// and every ant deposits 1 / C on each edge of its tour, where C is the cost of the tour.
+// This is the default strategy
+type AntCycleStrategy struct{}
+
+func (AntCycleStrategy) Evaporate(colony *AntColony) {
+	colony.EvaporatePheromones()
+}
+
+func (AntCycleStrategy) Deposit(colony *AntColony, ants []Ant) {
+	for i := range ants {
+		ants[i].DepositPheromones(colony)
+	}
+}
+
+// The Elitist Ant System update: on top of the ant-cycle update, the best-so-far tour receives an extra
+// e / C_bs on each of its edges, where C_bs is its cost. The weight e is constant unless a schedule is set
+// with WithElitistSchedule
+type ElitistStrategy struct {
+	// The elitist weight e, usually around the number of ants. If 0, it's the number of ants
+	Weight float64
+}
+
+func (strategy ElitistStrategy) validate() error {
+	if math.IsNaN(strategy.Weight) || math.IsInf(strategy.Weight, 0) || strategy.Weight < 0 {
+		return fmt.Errorf("%w: elitist weight must be non-negative and finite, got %f", ErrInvalidParams, strategy.Weight)
+	}
+
+	return nil
+}
+
+func (ElitistStrategy) Evaporate(colony *AntColony) {
+	colony.EvaporatePheromones()
+}
+
+func (strategy ElitistStrategy) Deposit(colony *AntColony, ants []Ant) {
+	AntCycleStrategy{}.Deposit(colony, ants)
+
+	if len(colony.bestTour) == 0 {
+		return
+	}
+
+	weight := strategy.Weight
+
+	if weight == 0 {
+		weight = float64(len(colony.ants))
+	}
+
+	if colony.elitistSchedule != nil {
+		weight = colony.elitistSchedule(colony.iterations-colony.runStart, colony.runLength)
+	}
+
+	colony.DepositTour(colony.bestTour, weight/colony.bestCost)
+}
+
+// The default number of ranks w in the rank-based Ant System
+const defaultRankWidth = 6
+
+// The rank-based Ant System (ASrank) update: after evaporation, the ants are sorted by the cost of their tours, and
+// only the w - 1 best deposit, the r-th best (from r = 1) depositing (w - r) / C_r on each edge of its tour. The
+// best-so-far tour gets the largest deposit, w / C_bs. Incomplete tours are never ranked
+type RankStrategy struct {
+	// The number of ranks w. If 0, it's 6
+	Width int
+}
+
+func (strategy RankStrategy) validate() error {
+	if strategy.Width < 0 {
+		return fmt.Errorf("%w: rank width must be non-negative, got %d", ErrInvalidParams, strategy.Width)
+	}
+
+	return nil
+}
+
+func (RankStrategy) Evaporate(colony *AntColony) {
+	colony.EvaporatePheromones()
+}
+
+func (strategy RankStrategy) Deposit(colony *AntColony, ants []Ant) {
+	width := strategy.Width
+
+	if width == 0 {
+		width = defaultRankWidth
+	}
+
+	ranked := make([]int, 0, len(ants))
+	costs := make([]float64, len(ants))
+
+	for i := range ants {
+		if colony.IsComplete(&ants[i]) {
+			ranked = append(ranked, i)
+			costs[i] = colony.tourCost(ants[i].tour)
+		}
+	}
+
+	sort.SliceStable(ranked, func(a, b int) bool { return costs[ranked[a]] < costs[ranked[b]] })
+
+	for r := 1; r < width && r <= len(ranked); r++ {
+		i := ranked[r-1]
+		colony.DepositTour(ants[i].tour, float64(width-r)/costs[i])
+	}
+
+	if len(colony.bestTour) > 0 {
+		colony.DepositTour(colony.bestTour, float64(width)/colony.bestCost)
+	}
+}
+
+// The Best-Worst Ant System update: after evaporation, only the best-so-far tour deposits 1 / C_bs, and the edges of
+// the iteration's worst tour that aren't on the best-so-far tour evaporate a second time, so the colony actively
+// forgets the worst choices. Then every trail is mutated with probability MutationRate, moving up or down (with
+// equal probability) by MutationStrength times the mean pheromone on the best-so-far tour; mutations that would
+// leave a trail non-positive are skipped. BWAS also restarts on stagnation, which WithRestartOnStagnation provides
+type BestWorstStrategy struct {
+	// The probability of mutating each trail in an iteration, in [0, 1]. 0 disables the mutation
+	MutationRate float64
+	// The size of a mutation relative to the mean pheromone on the best-so-far tour
+	MutationStrength float64
+}
+
+func (strategy BestWorstStrategy) validate() error {
+	if !(strategy.MutationRate >= 0 && strategy.MutationRate <= 1) {
+		return fmt.Errorf("%w: mutation rate must be within [0, 1], got %v", ErrInvalidParams, strategy.MutationRate)
+	}
+
+	if math.IsNaN(strategy.MutationStrength) || math.IsInf(strategy.MutationStrength, 0) || strategy.MutationStrength < 0 {
+		return fmt.Errorf("%w: mutation strength must be non-negative and finite, got %f", ErrInvalidParams, strategy.MutationStrength)
+	}
+
+	return nil
+}
+
+func (BestWorstStrategy) Evaporate(colony *AntColony) {
+	colony.EvaporatePheromones()
+}
+
+func (strategy BestWorstStrategy) Deposit(colony *AntColony, ants []Ant) {
+	if len(colony.bestTour) == 0 {
+		return
+	}
+
+	colony.DepositTour(colony.bestTour, 1/colony.bestCost)
+
+	best := make(map[Edge]bool, len(colony.bestTour))
+
+	for _, edge := range colony.bestTour {
+		colony.forEachDirection(edge, func(e Edge) { best[e] = true })
+	}
+
+	if worst := colony.iterationWorst(ants); worst != nil {
+		for _, edge := range worst {
+			if !best[edge] {
+				colony.forEachDirection(edge, func(e Edge) { colony.Pheromones[e.A][colony.column(e)] *= 1 - colony.rho })
+			}
+		}
+	}
+
+	if strategy.MutationRate > 0 {
+		colony.mutateTrails(strategy.MutationRate, strategy.MutationStrength*colony.meanPheromone(colony.bestTour))
+	}
+}
+
+// The most expensive complete tour among the ants, or nil if no ant completed its tour
+func (colony *AntColony) iterationWorst(ants []Ant) []Edge {
+	var worst []Edge
+	worstCost := math.Inf(-1)
+
+	for i := range ants {
+		if !colony.IsComplete(&ants[i]) {
+			continue
+		}
+
+		if cost := colony.tourCost(ants[i].tour); cost > worstCost {
+			worst = ants[i].tour
+			worstCost = cost
+		}
+	}
+
+	return worst
+}
+
+// The mean pheromone on the edges of a tour
+func (colony *AntColony) meanPheromone(tour []Edge) float64 {
+	sum := 0.0
+
+	for _, edge := range tour {
+		sum += colony.Pheromones[edge.A][colony.column(edge)]
+	}
+
+	return sum / float64(len(tour))
+}
+
+// Move each trail (other than self-loops) up or down by amount with probability rate, keeping it positive. In an
+// undirected graph both directions of a connection move together, drawn once for the direction with a < b
+func (colony *AntColony) mutateTrails(rate float64, amount float64) {
+	directed := colony.constructionGraph.Directed
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A == edge.B || (!directed && edge.A > edge.B) || colony.rng.Float64() >= rate {
+				continue
+			}
+
+			delta := amount
+
+			if colony.rng.Float64() < 0.5 {
+				delta = -amount
+			}
+
+			colony.forEachDirection(edge, func(e Edge) {
+				if tau := colony.Pheromones[e.A][colony.column(e)] + delta; tau > 0 {
+					colony.Pheromones[e.A][colony.column(e)] = tau
+				}
+			})
+		}
+	}
+}
+
+// The default population size of the population-based ACO
+const defaultPopulationSize = 5
+
+// The population-based ACO (P-ACO) update: instead of evaporating the whole matrix, the colony keeps a FIFO
+// population of the last Size iteration-best tours, and the trails are the initial pheromones plus a fixed delta for
+// every tour of the population that takes the edge. A tour entering the population adds its deposit, and the oldest
+// tour leaving it takes its deposit back, so an iteration only touches O(Size * n) entries. delta is Intensity / Size
+// times the mean initial pheromone tau_0, so an edge taken by the whole population has (1 + Intensity) * tau_0.
+// The population holds state, so the strategy is used by pointer (e.g. &PopulationStrategy{Size: 5}) and must not
+// be shared between colonies. It's cleared when the colony starts over (after creation or Reset); after a restart
+// on stagnation, the trails are never withdrawn below their initial values
+type PopulationStrategy struct {
+	// The number of tours in the population. If 0, it's 5
+	Size int
+	// How much pheromone the whole population can add to an edge, relative to tau_0. If 0, it's n - 2 (but at least 1)
+	// for n components, so that with tau_0 = 1 / (n - 1) the trails range over [1 / (n - 1), 1] as in the original P-ACO
+	Intensity float64
+	// The tours of the population, from the oldest to the newest
+	population [][]Edge
+}
+
+func (strategy *PopulationStrategy) validate() error {
+	if strategy.Size < 0 {
+		return fmt.Errorf("%w: population size must be non-negative, got %d", ErrInvalidParams, strategy.Size)
+	}
+
+	if math.IsNaN(strategy.Intensity) || math.IsInf(strategy.Intensity, 0) || strategy.Intensity < 0 {
+		return fmt.Errorf("%w: population intensity must be non-negative and finite, got %f", ErrInvalidParams, strategy.Intensity)
+	}
+
+	return nil
+}
+
+// The trails only change as tours enter and leave the population
+func (*PopulationStrategy) Evaporate(colony *AntColony) {}
+
+func (strategy *PopulationStrategy) Deposit(colony *AntColony, ants []Ant) {
+	size := strategy.Size
+
+	if size == 0 {
+		size = defaultPopulationSize
+	}
+
+	intensity := strategy.Intensity
+
+	if intensity == 0 {
+		intensity = math.Max(float64(len(colony.constructionGraph.Nodes)-2), 1)
+	}
+
+	// The colony's trails were just reset to their initial values, so the population no longer applies
+	if colony.iterations == 0 {
+		strategy.population = nil
+	}
+
+	tour, _ := colony.iterationBest(ants)
+
+	if tour == nil {
+		return
+	}
+
+	delta := intensity / float64(size) * colony.meanInitialPheromone()
+
+	if len(strategy.population) == size {
+		colony.withdrawTour(strategy.population[0], delta)
+		strategy.population = strategy.population[1:]
+	}
+
+	entering := make([]Edge, len(tour))
+	copy(entering, tour)
+	strategy.population = append(strategy.population, entering)
+	colony.DepositTour(entering, delta)
+}
+
+// The mean initial pheromone on the edges of the graph (other than self-loops)
+func (colony *AntColony) meanInitialPheromone() float64 {
+	sum := 0.0
+	count := 0
+
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A != edge.B {
+				sum += colony.initialPheromones[edge.A][colony.column(edge)]
+				count++
+			}
+		}
+	}
+
+	return sum / float64(count)
+}
+
+// The Ant Colony System global update: only the best-so-far tour is updated, each of its edges moving towards
+// 1 / C_bs as tau = (1 - rho) * tau + rho / C_bs. The other trails don't evaporate here; in ACS they decay as the ants
+// take them, with WithLocalPheromoneUpdate, and the ants choose with the pseudorandom proportional rule set with
+// WithPseudoRandomProportional. Together these make up ACS. ACS expects the initial pheromones tau_0 to be well below
+// 1 / C_bs, as with the usual tau_0 = 1 / (n * C_nn) for a nearest-neighbour tour of cost C_nn; with larger initial
+// pheromones (such as TSPProblem's m / C_nn) the updates make the best tour less attractive than the untouched edges
+type ACSStrategy struct{}
+
+// Evaporation is folded into the deposit, and only applies to the best-so-far tour
+func (ACSStrategy) Evaporate(colony *AntColony) {}
+
+func (ACSStrategy) Deposit(colony *AntColony, ants []Ant) {
+	if len(colony.bestTour) == 0 {
+		return
+	}
+
+	for _, edge := range colony.bestTour {
+		colony.forEachDirection(edge, func(e Edge) { colony.Pheromones[e.A][colony.column(e)] *= 1 - colony.rho })
+	}
+
+	colony.DepositTour(colony.bestTour, colony.rho/colony.bestCost)
+}
+
+// The MAX-MIN Ant System update: only the iteration-best tour (or the best-so-far one) deposits 1 / C, and the
+// trails are kept within [tau_min, tau_max], where tau_max = 1 / (rho * C_bs) for the best-so-far cost C_bs and
+// tau_min is a fraction of it. The bounds keep every edge possible while letting the best tours dominate. The
+// trails start at tau_max: in the first iteration (after creation or Reset) every trail is set to it before the
+// deposit. They're set back to it once the best-so-far hasn't improved for ReinitAfter iterations, which also resets
+// the count that WithRestartOnStagnation uses
+type MaxMinStrategy struct {
+	// Deposit on the best-so-far tour instead of the iteration-best one
+	GlobalBest bool
+	// tau_min / tau_max, in [0, 1). If 0, it's 1 / (2n) for n components
+	MinRatio float64
+	// After how many iterations without improvement the trails are reinitialized (0 if never)
+	ReinitAfter int
+}
+
+func (strategy MaxMinStrategy) validate() error {
+	if math.IsNaN(strategy.MinRatio) || strategy.MinRatio < 0 || strategy.MinRatio >= 1 {
+		return fmt.Errorf("%w: MMAS min ratio must be in [0, 1), got %f", ErrInvalidParams, strategy.MinRatio)
+	}
+
+	if strategy.ReinitAfter < 0 {
+		return fmt.Errorf("%w: MMAS reinitialization interval must be non-negative, got %d", ErrInvalidParams, strategy.ReinitAfter)
+	}
+
+	return nil
+}
+
+func (MaxMinStrategy) Evaporate(colony *AntColony) {
+	colony.EvaporatePheromones()
+}
+
+func (strategy MaxMinStrategy) Deposit(colony *AntColony, ants []Ant) {
+	// Without a complete tour there is nothing to deposit, and no bounds either
+	if len(colony.bestTour) == 0 {
+		return
+	}
+
+	tauMax := 1 / (colony.rho * colony.bestCost)
+	ratio := strategy.MinRatio
+
+	if ratio == 0 {
+		ratio = 1 / (2 * float64(len(colony.constructionGraph.Nodes)))
+	}
+
+	if colony.iterations == 0 || (strategy.ReinitAfter > 0 && colony.stagnation >= strategy.ReinitAfter) {
+		colony.fillTrails(tauMax)
+		colony.stagnation = 0
+	}
+
+	tour, cost := colony.bestTour, colony.bestCost
+
+	if !strategy.GlobalBest {
+		tour, cost = colony.iterationBest(ants)
+	}
+
+	if len(tour) > 0 {
+		colony.DepositTour(tour, 1/cost)
+	}
+
+	colony.clampTrails(ratio*tauMax, tauMax)
+}
+
+// The cheapest complete tour among the ants, and its cost. The tour is nil if no ant completed its tour
+func (colony *AntColony) iterationBest(ants []Ant) ([]Edge, float64) {
+	var best []Edge
+	bestCost := math.Inf(1)
+
+	for i := range ants {
+		if !colony.IsComplete(&ants[i]) {
+			continue
+		}
+
+		if cost := colony.tourCost(ants[i].tour); cost < bestCost {
+			best = ants[i].tour
+			bestCost = cost
+		}
+	}
+
+	return best, bestCost
+}
+
+// Set the pheromone on every edge of the graph (other than self-loops) to tau
+func (colony *AntColony) fillTrails(tau float64) {
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A != edge.B {
+				colony.Pheromones[edge.A][colony.column(edge)] = tau
+			}
+		}
+	}
+}
+
+// Keep the pheromone on every edge of the graph (other than self-loops) within [low, high]
+func (colony *AntColony) clampTrails(low float64, high float64) {
+	for _, edges := range colony.constructionGraph.Edges {
+		for _, edge := range edges {
+			if edge.A != edge.B {
+				colony.Pheromones[edge.A][colony.column(edge)] = math.Min(math.Max(colony.Pheromones[edge.A][colony.column(edge)], low), high)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package antcolony
+
+import (
+	"reflect"
+	"testing"
+)
+
+// A trivial custom strategy that leaves the trails alone and counts how it's called
+type countingStrategy struct {
+	evaporations int
+	deposits     int
+	ants         int
+}
+
+func (strategy *countingStrategy) Evaporate(colony *AntColony) {
+	strategy.evaporations++
+}
+
+func (strategy *countingStrategy) Deposit(colony *AntColony, ants []Ant) {
+	strategy.deposits++
+	strategy.ants += len(ants)
+}
+
+func TestCustomPheromoneStrategy(t *testing.T) {
+	strategy := &countingStrategy{}
+	colony, err := NewAntColony(NewTSPProblem(ringWeights(t, 5)), WithSeed(1), WithAnts(4), WithPheromoneStrategy(strategy))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	initial := copyMatrix(colony.Pheromones)
+	colony.RunSimulation(3)
+
+	if strategy.evaporations != 3 || strategy.deposits != 3 {
+		t.Errorf("got %d evaporations and %d deposits in 3 iterations, expected 3 of each", strategy.evaporations, strategy.deposits)
+	}
+
+	if strategy.ants != 12 {
+		t.Errorf("got %d ants passed to Deposit, expected 12", strategy.ants)
+	}
+
+	if !reflect.DeepEqual(colony.Pheromones, initial) {
+		t.Error("the trails changed, but the strategy never touched them")
+	}
+}
@@ -0,0 +1,88 @@
+package antcolony
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const (
+	stressInstances = 1000
+	stressMinCities = 4
+	stressMaxCities = 8
+	// ACO isn't guaranteed to find the optimum (a few random instances have tiny weights that make the
+	// heuristic dominate), but on instances this small it should never be far off
+	stressMaxRatio = 2.0
+)
+
+// A symmetric instance with random weights in [0, 1)
+func randomSymmetricWeights(rng *rand.Rand, n int) [][]float64 {
+	weights := make([][]float64, n)
+
+	for i := range weights {
+		weights[i] = make([]float64, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			w := rng.Float64()
+			weights[i][j] = w
+			weights[j][i] = w
+		}
+	}
+
+	return weights
+}
+
+// Solve many random small instances with both brute force and ACO, and check that every ACO tour is valid and
+// within a reasonable factor of the optimum. Instances are kept small (n <= 8) so brute force stays fast; run with
+// -race to also check the concurrent construction, which every tenth instance uses
+func TestStressRandomInstances(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the stress test in short mode")
+	}
+
+	rng := rand.New(rand.NewSource(1337))
+	worstRatio := 1.0
+
+	for i := 0; i < stressInstances; i++ {
+		n := stressMinCities + rng.Intn(stressMaxCities-stressMinCities+1)
+		weights := randomSymmetricWeights(rng, n)
+		_, optimal, err := BruteForceTSP(weights)
+
+		if err != nil {
+			t.Fatalf("instance %d: %v", i, err)
+		}
+
+		opts := []Option{WithAnts(10), WithSeed(int64(i)), WithTourCost(SumCost(weights))}
+
+		if i%10 == 0 {
+			opts = append(opts, WithWorkers(4))
+		}
+
+		colony, err := NewAntColony(NewTSPProblem(weights), opts...)
+
+		if err != nil {
+			t.Fatalf("instance %d: %v", i, err)
+		}
+
+		colony.RunSimulation(20)
+		tour, cost := colony.BestSolution()
+
+		if err := ValidateTour(tour, n); err != nil {
+			t.Errorf("instance %d: invalid tour: %v", i, err)
+			continue
+		}
+
+		ratio := cost / optimal
+
+		if ratio > worstRatio {
+			worstRatio = ratio
+		}
+
+		if ratio > stressMaxRatio {
+			t.Errorf("instance %d: ACO cost %v is %.2fx the optimum %v", i, cost, ratio, optimal)
+		}
+	}
+
+	t.Logf("worst ratio to the optimum: %.4f", worstRatio)
+}
@@ -0,0 +1,115 @@
+package antcolony
+
+import "math"
+
+// What to do when a tour ties the best-so-far cost, i.e. equals it up to the colony's tolerance (see WithEpsilon).
+// This matters for problems with many equal-cost optima, e.g. symmetric instances
+type TieBreakPolicy int
+
+const (
+	// Keep the tour that reached the best cost first. This is the default
+	TieKeepFirst TieBreakPolicy = iota
+	// Replace the best-so-far with a tying tour if it differs from all the recent bests, so the reported
+	// best keeps moving to new optima instead of sticking to the first one found
+	TiePreferDiverse
+	// Keep the first tour as the best-so-far, but also collect the distinct equal-cost optima,
+	// which are available with BestSolutions
+	TieKeepAll
+)
+
+const (
+	// How many of the most recent best-so-far tours TiePreferDiverse compares against
+	numRecentBests = 8
+	// The maximum number of equal-cost optima collected by TieKeepAll
+	maxOptima = 100
+)
+
+// Handle a tour that ties the best-so-far cost according to the tie-breaking policy
+func (colony *AntColony) handleTie(tour []Edge) {
+	switch colony.tieBreak {
+	case TiePreferDiverse:
+		for _, recent := range colony.recentBests {
+			if edgeDifference(tour, recent) == 0 {
+				return
+			}
+		}
+
+		colony.setBest(tour, colony.bestCost)
+	case TieKeepAll:
+		if len(colony.optima) >= maxOptima {
+			return
+		}
+
+		for _, optimum := range colony.optima {
+			if edgeDifference(tour, optimum) == 0 {
+				return
+			}
+		}
+
+		optimum := make([]Edge, len(tour))
+		copy(optimum, tour)
+		colony.optima = append(colony.optima, optimum)
+	}
+}
+
+// The distinct tours sharing the best-so-far cost. Unless the TieKeepAll policy is used, this is
+// just the best-so-far tour. The tours are copies
+func (colony *AntColony) BestSolutions() [][]Edge {
+	solutions := make([][]Edge, 0, len(colony.optima))
+
+	for _, optimum := range colony.optima {
+		solution := make([]Edge, len(optimum))
+		copy(solution, optimum)
+		solutions = append(solutions, solution)
+	}
+
+	return solutions
+}
+
+// Keep only the tours with the lowest cost
+func (colony *AntColony) bestOf(tours [][]Edge) [][]Edge {
+	best := make([][]Edge, 0)
+	bestCost := math.Inf(1)
+
+	for _, tour := range tours {
+		cost := colony.tourCost(tour)
+
+		if colony.improves(cost, bestCost) {
+			best = [][]Edge{tour}
+			bestCost = cost
+		} else if colony.ties(cost, bestCost) {
+			best = append(best, tour)
+		}
+	}
+
+	return best
+}
+
+// The number of edges in a that aren't in b. Edges are compared regardless of direction,
+// so a tour and its reversal are considered the same
+func edgeDifference(a []Edge, b []Edge) int {
+	inB := make(map[Edge]bool)
+
+	for _, edge := range b {
+		inB[undirected(edge)] = true
+	}
+
+	diff := 0
+
+	for _, edge := range a {
+		if !inB[undirected(edge)] {
+			diff++
+		}
+	}
+
+	return diff
+}
+
+// The edge with its endpoints in a canonical order
+func undirected(edge Edge) Edge {
+	if edge.A > edge.B {
+		return Edge{A: edge.B, B: edge.A}
+	}
+
+	return edge
+}
@@ -0,0 +1,32 @@
+package antcolony
+
+import "math"
+
+// The default tolerance when comparing costs, relative to their magnitude
+const defaultEpsilon = 1e-9
+
+// Does cost improve on reference by more than the colony's tolerance? The tolerance is epsilon relative to the
+// reference's magnitude (or absolute, for references below 1), so tours whose costs only differ by floating-point
+// jitter, e.g. from summing the same edges in a different order, don't count as improvements
+func (colony *AntColony) improves(cost float64, reference float64) bool {
+	return improves(cost, reference, colony.epsilon)
+}
+
+// Are the costs equal up to the colony's tolerance?
+func (colony *AntColony) ties(cost float64, reference float64) bool {
+	return math.Abs(cost-reference) <= tolerance(reference, colony.epsilon)
+}
+
+// Does cost improve on reference by more than epsilon, relative to the reference's magnitude?
+func improves(cost float64, reference float64, epsilon float64) bool {
+	return cost < reference-tolerance(reference, epsilon)
+}
+
+func tolerance(reference float64, epsilon float64) float64 {
+	// Before any tour is recorded the reference is infinite, and any finite cost improves on it
+	if math.IsInf(reference, 0) {
+		return 0
+	}
+
+	return epsilon * math.Max(1, math.Abs(reference))
+}
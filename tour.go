@@ -0,0 +1,253 @@
+package antcolony
+
+import "fmt"
+
+// Check that a tour is a Hamiltonian cycle over n components: it must have exactly n edges,
+// each edge must start where the previous one ended, and every component must be visited exactly once.
+// Edges are treated as directed, i.e. (a, b) is never considered the same as (b, a),
+// so this works for both symmetric and asymmetric problems
+func ValidateTour(tour []Edge, n int) error {
+	if len(tour) != n {
+		return fmt.Errorf("%w: tour has %d edges, expected %d", ErrInfeasibleTour, len(tour), n)
+	}
+
+	visited := make([]bool, n)
+
+	for i, edge := range tour {
+		// Compare as uint, since converting a huge index to an int could wrap around to a negative number
+		if edge.A >= uint(n) || edge.B >= uint(n) {
+			return fmt.Errorf("%w: edge %d (%d, %d) references a component outside [0, %d)", ErrInfeasibleTour, i, edge.A, edge.B, n)
+		}
+
+		if visited[edge.A] {
+			return fmt.Errorf("%w: component %d is visited more than once", ErrInfeasibleTour, edge.A)
+		}
+
+		visited[edge.A] = true
+		// The next edge should start where this one ends (the last edge closes the cycle)
+		next := tour[(i+1)%len(tour)]
+
+		if edge.B != next.A {
+			return fmt.Errorf("%w: edge %d (%d, %d) is not followed by an edge leaving %d", ErrInfeasibleTour, i, edge.A, edge.B, edge.B)
+		}
+	}
+
+	return nil
+}
+
+// Find the cycles formed by the edges of a tour over n components, each given as the components in the order
+// they're visited. A valid tour forms exactly one cycle covering all n components, so several shorter cycles
+// (subtours) point to a construction that closed too early. The edges are treated as directed, and are followed
+// from components 0, 1, ... in turn, with the cycles returned in the order they're reached. Edges outside [0, n)
+// are ignored, and if a component is left by several edges only the first one is followed. Paths that don't close
+// aren't cycles, so they aren't returned; use ValidateTour to find out why a tour with a single cycle is invalid
+func FindSubtours(tour []Edge, n int) [][]uint {
+	next := make([]int, n)
+
+	for i := range next {
+		next[i] = -1
+	}
+
+	for _, edge := range tour {
+		if edge.A < uint(n) && edge.B < uint(n) && next[edge.A] == -1 {
+			next[edge.A] = int(edge.B)
+		}
+	}
+
+	cycles := make([][]uint, 0)
+	// 0 if not yet reached, otherwise the number of the walk that reached the component
+	walks := make([]int, n)
+
+	for start := 0; start < n; start++ {
+		if walks[start] != 0 {
+			continue
+		}
+
+		walk := start + 1
+		path := make([]uint, 0)
+		curr := start
+
+		for curr != -1 && walks[curr] == 0 {
+			walks[curr] = walk
+			path = append(path, uint(curr))
+			curr = next[curr]
+		}
+
+		// The walk closed on itself, so the part of the path from where it closed is a cycle
+		if curr != -1 && walks[curr] == walk {
+			for i, component := range path {
+				if component == uint(curr) {
+					cycles = append(cycles, path[i:])
+					break
+				}
+			}
+		}
+	}
+
+	return cycles
+}
+
+// Convert a tour given as a list of edges into the order in which the components are visited,
+// starting from the first component of the first edge
+func TourOrder(tour []Edge) []uint {
+	order := make([]uint, 0, len(tour))
+
+	for _, edge := range tour {
+		order = append(order, edge.A)
+	}
+
+	return order
+}
+
+// Convert the order in which the components are visited back into a tour, closing the cycle
+func tourFromOrder(order []uint) []Edge {
+	tour := make([]Edge, 0, len(order))
+
+	for i := range order {
+		tour = append(tour, Edge{A: order[i], B: order[(i+1)%len(order)]})
+	}
+
+	return tour
+}
+
+// Check that an order visits each of the components 0, ..., len(order) - 1 exactly once
+func validateOrder(order []uint) error {
+	visited := make([]bool, len(order))
+
+	for _, component := range order {
+		if component >= uint(len(order)) {
+			return fmt.Errorf("%w: component %d is outside [0, %d)", ErrInfeasibleTour, component, len(order))
+		}
+
+		if visited[component] {
+			return fmt.Errorf("%w: component %d is visited more than once", ErrInfeasibleTour, component)
+		}
+
+		visited[component] = true
+	}
+
+	return nil
+}
+
+// Convert the order in which the components are visited into a tour, closing the cycle.
+// The order must be a permutation of 0, ..., n - 1
+func TourFromOrder(order []uint) ([]Edge, error) {
+	if err := validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	return tourFromOrder(order), nil
+}
+
+// Convert a tour into its successor form, where succ[i] is the component visited right after i. This allows
+// O(1) neighbour queries, e.g. in local search. The tour must be valid, as checked by ValidateTour
+func TourSuccessors(tour []Edge, n int) ([]uint, error) {
+	if err := ValidateTour(tour, n); err != nil {
+		return nil, err
+	}
+
+	succ := make([]uint, n)
+
+	for _, edge := range tour {
+		succ[edge.A] = edge.B
+	}
+
+	return succ, nil
+}
+
+// Convert a successor form into the order in which the components are visited, starting from component 0.
+// The successors must form a single cycle through all the components
+func SuccessorOrder(succ []uint) ([]uint, error) {
+	n := len(succ)
+	order := make([]uint, 0, n)
+	visited := make([]bool, n)
+	curr := uint(0)
+
+	for i := 0; i < n; i++ {
+		if visited[curr] {
+			return nil, fmt.Errorf("%w: successors close a cycle after %d of %d components", ErrInfeasibleTour, i, n)
+		}
+
+		visited[curr] = true
+		order = append(order, curr)
+
+		if succ[curr] >= uint(n) {
+			return nil, fmt.Errorf("%w: successor %d of component %d is outside [0, %d)", ErrInfeasibleTour, succ[curr], curr, n)
+		}
+
+		curr = succ[curr]
+	}
+
+	// After n steps we must be back where we started
+	if n > 0 && curr != 0 {
+		return nil, fmt.Errorf("%w: successors don't lead back to component 0", ErrInfeasibleTour)
+	}
+
+	return order, nil
+}
+
+// Convert a successor form into a tour starting at component 0.
+// The successors must form a single cycle through all the components
+func TourFromSuccessors(succ []uint) ([]Edge, error) {
+	order, err := SuccessorOrder(succ)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tourFromOrder(order), nil
+}
+
+// Convert the order in which the components are visited into a successor form.
+// The order must be a permutation of 0, ..., n - 1
+func OrderSuccessors(order []uint) ([]uint, error) {
+	if err := validateOrder(order); err != nil {
+		return nil, err
+	}
+
+	succ := make([]uint, len(order))
+
+	for i, component := range order {
+		succ[component] = order[(i+1)%len(order)]
+	}
+
+	return succ, nil
+}
+
+// A canonical form for symmetric tours, which are equivalent under rotation and reflection: the visiting order,
+// rotated to start at the smallest component (0 for a complete tour), and oriented so that the second component is
+// the smaller of the first component's two neighbours. Two tours are the same solution of a symmetric problem
+// exactly when their canonical forms are equal
+func CanonicalTour(tour []Edge) []uint {
+	order := TourOrder(tour)
+	n := len(order)
+
+	if n == 0 {
+		return order
+	}
+
+	first := 0
+
+	for i, component := range order {
+		if component < order[first] {
+			first = i
+		}
+	}
+
+	canonical := make([]uint, 0, n)
+	next := order[(first+1)%n]
+	prev := order[(first-1+n)%n]
+
+	// Walk forwards if the successor is the smaller neighbour, otherwise walk backwards
+	step := 1
+
+	if prev < next {
+		step = n - 1
+	}
+
+	for i := 0; i < n; i++ {
+		canonical = append(canonical, order[(first+i*step)%n])
+	}
+
+	return canonical
+}
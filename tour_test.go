@@ -0,0 +1,134 @@
+package antcolony
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCanonicalTourIgnoresRotationAndReflection(t *testing.T) {
+	order := []uint{0, 3, 1, 4, 2}
+	expected := []uint{0, 2, 4, 1, 3}
+	n := len(order)
+
+	for _, reflected := range []bool{false, true} {
+		oriented := slices.Clone(order)
+
+		if reflected {
+			slices.Reverse(oriented)
+		}
+
+		for shift := 0; shift < n; shift++ {
+			rotated := append(slices.Clone(oriented[shift:]), oriented[:shift]...)
+			tour, err := TourFromOrder(rotated)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if canonical := CanonicalTour(tour); !slices.Equal(canonical, expected) {
+				t.Errorf("order %v has canonical form %v, expected %v", rotated, canonical, expected)
+			}
+		}
+	}
+
+	// A different cycle through the same components must not collapse onto the same form
+	other, err := TourFromOrder([]uint{0, 1, 3, 4, 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if canonical := CanonicalTour(other); slices.Equal(canonical, expected) {
+		t.Errorf("a different tour got the same canonical form %v", canonical)
+	}
+}
+
+func TestTourConversionsRoundTrip(t *testing.T) {
+	orders := [][]uint{
+		{0},
+		{0, 1},
+		{0, 3, 1, 4, 2},
+		{0, 5, 4, 3, 2, 1},
+	}
+
+	for _, order := range orders {
+		n := len(order)
+		tour, err := TourFromOrder(order)
+
+		if err != nil {
+			t.Fatalf("%v: %v", order, err)
+		}
+
+		if got := TourOrder(tour); !slices.Equal(got, order) {
+			t.Errorf("%v: tour order is %v", order, got)
+		}
+
+		succ, err := TourSuccessors(tour, n)
+
+		if err != nil {
+			t.Fatalf("%v: %v", order, err)
+		}
+
+		if fromOrder, err := OrderSuccessors(order); err != nil || !slices.Equal(fromOrder, succ) {
+			t.Errorf("%v: successors of the order are %v (%v), expected %v", order, fromOrder, err, succ)
+		}
+
+		// The orders all start at 0, which is where the successor form is walked from
+		if got, err := SuccessorOrder(succ); err != nil || !slices.Equal(got, order) {
+			t.Errorf("%v: order of the successors is %v (%v)", order, got, err)
+		}
+
+		if got, err := TourFromSuccessors(succ); err != nil || !slices.Equal(got, tour) {
+			t.Errorf("%v: tour of the successors is %v (%v), expected %v", order, got, err, tour)
+		}
+	}
+}
+
+func TestTourConversionsRejectInvalidInput(t *testing.T) {
+	orders := []struct {
+		name  string
+		order []uint
+	}{
+		{"component out of range", []uint{0, 3, 1}},
+		{"repeated component", []uint{0, 1, 1}},
+	}
+
+	for _, test := range orders {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := TourFromOrder(test.order); !errors.Is(err, ErrInfeasibleTour) {
+				t.Errorf("TourFromOrder: got %v, expected %v", err, ErrInfeasibleTour)
+			}
+
+			if _, err := OrderSuccessors(test.order); !errors.Is(err, ErrInfeasibleTour) {
+				t.Errorf("OrderSuccessors: got %v, expected %v", err, ErrInfeasibleTour)
+			}
+		})
+	}
+
+	successors := []struct {
+		name string
+		succ []uint
+	}{
+		{"two cycles", []uint{1, 0, 3, 2}},
+		{"successor out of range", []uint{1, 4, 0}},
+		{"fixed point", []uint{0, 2, 1}},
+		{"path into a cycle", []uint{1, 2, 1}},
+	}
+
+	for _, test := range successors {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := SuccessorOrder(test.succ); !errors.Is(err, ErrInfeasibleTour) {
+				t.Errorf("SuccessorOrder: got %v, expected %v", err, ErrInfeasibleTour)
+			}
+
+			if _, err := TourFromSuccessors(test.succ); !errors.Is(err, ErrInfeasibleTour) {
+				t.Errorf("TourFromSuccessors: got %v, expected %v", err, ErrInfeasibleTour)
+			}
+		})
+	}
+
+	if _, err := TourSuccessors([]Edge{{A: 0, B: 1}, {A: 1, B: 2}}, 3); !errors.Is(err, ErrInfeasibleTour) {
+		t.Errorf("TourSuccessors: got %v for an open tour, expected %v", err, ErrInfeasibleTour)
+	}
+}
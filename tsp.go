@@ -0,0 +1,197 @@
+package antcolony
+
+// Defaults used when solving a TSP with SolveTSP or TSPProblem.Solve
+const (
+	// Number of iterations to run the simulation for
+	defaultTSPIters = 100
+	// Lower bound on the number of ants; otherwise one ant per city is used
+	minTSPAnts = 10
+)
+
+// A (possibly asymmetric) traveling salesman problem over a complete graph,
+// where weights[i][j] is the cost of going from city i to city j
+type TSPProblem struct {
+	graph   Graph
+	weights [][]float64
+	// How to solve the problem with Solve; zero values mean the defaults
+	Params TSPParams
+}
+
+// Parameters for solving a TSP, e.g. read from a JSON problem spec. Zero values mean the defaults
+type TSPParams struct {
+	// Number of ants; defaults to one per city (but at least 10)
+	Ants uint `json:"ants,omitempty"`
+	// Number of iterations to run the simulation for; defaults to 100
+	Iterations int `json:"iterations,omitempty"`
+	// Seed for the random source; if omitted, runs aren't reproducible
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// Construct a TSP over a complete graph from a square weight matrix. The graph is undirected if the weights are
+// symmetric, so a tour reinforces both directions of its edges, and directed otherwise. If the matrix isn't square,
+// NewAntColony and Solve return ErrRaggedMatrix
+func NewTSPProblem(weights [][]float64) *TSPProblem {
+	graph := NewCompleteGraph(uint(len(weights)))
+	graph.Directed = !symmetric(weights)
+
+	return &TSPProblem{graph: graph, weights: weights}
+}
+
+// Is the matrix square and equal to its transpose?
+func symmetric(matrix [][]float64) bool {
+	for i := range matrix {
+		if len(matrix[i]) != len(matrix) {
+			return false
+		}
+
+		for j := 0; j < i; j++ {
+			if matrix[i][j] != matrix[j][i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// The length of a cycle found with a greedy nearest-neighbour search starting at city 0.
+// Ties are broken in favour of the city with the lowest index, so the length is deterministic
+func (tsp *TSPProblem) greedySolution() float64 {
+	n := len(tsp.weights)
+	visited := make([]bool, n)
+	curr := 0
+	cost := 0.0
+
+	visited[curr] = true
+
+	for step := 1; step < n; step++ {
+		next := -1
+
+		for j := 0; j < n; j++ {
+			if !visited[j] && (next == -1 || tsp.weights[curr][j] < tsp.weights[curr][next]) {
+				next = j
+			}
+		}
+
+		cost += tsp.weights[curr][next]
+		visited[next] = true
+		curr = next
+	}
+
+	// Close the cycle
+	return cost + tsp.weights[curr][0]
+}
+
+// The cost of an edge is its weight
+func (tsp *TSPProblem) Cost(edge Edge) float64 {
+	return tsp.weights[edge.A][edge.B]
+}
+
+func (tsp *TSPProblem) ConstructGraph() Graph {
+	return tsp.graph
+}
+
+// The pheromones are initialized to m / C^{nn}, where m is the number of ants and C^{nn} is the
+// length of a nearest-neighbour tour. If that tour costs nothing (e.g. all the weights are 0), m / C^{nn}
+// would be infinite, so the pheromones are initialized to 1 instead
+func (tsp *TSPProblem) InitPheromones(num_ants uint) [][]float64 {
+	pheromones := make([][]float64, 0)
+	tau0 := 1.0
+
+	if greedy := tsp.greedySolution(); greedy > 0 {
+		tau0 = float64(num_ants) / greedy
+	}
+
+	for i := 0; i < len(tsp.weights); i++ {
+		pheromone := make([]float64, 0)
+
+		for j := 0; j < len(tsp.weights); j++ {
+			pheromone = append(pheromone, tau0)
+		}
+
+		pheromones = append(pheromones, pheromone)
+	}
+
+	return pheromones
+}
+
+// The heuristic of an edge is the repriocorial of its weight
+func (tsp *TSPProblem) InitHeuristics() [][]float64 {
+	heuristics := make([][]float64, 0)
+
+	for i := 0; i < len(tsp.weights); i++ {
+		heuristic := make([]float64, 0)
+
+		// A ragged weight matrix gives ragged heuristics, which NewAntColony rejects
+		for j := 0; j < len(tsp.weights[i]); j++ {
+			// There is no meaningful heuristic for a self-loop
+			if i == j {
+				heuristic = append(heuristic, 0)
+				continue
+			}
+
+			heuristic = append(heuristic, 1.0/(tsp.weights[i][j]+1e-8))
+		}
+
+		heuristics = append(heuristics, heuristic)
+	}
+
+	return heuristics
+}
+
+// Solve a TSP given by a square weight matrix in one call, returning the order in which the cities
+// are visited and the cost of the tour. The defaults are one ant per city (but at least 10 ants)
+// and 100 iterations; opts are passed on to the colony (e.g. WithSeed for reproducible results).
+// The cost is the sum of the weights unless another cost is given with WithTourCost
+func SolveTSP(weights [][]float64, opts ...Option) (order []uint, cost float64, err error) {
+	return NewTSPProblem(weights).Solve(opts...)
+}
+
+// Solve the problem according to its parameters, returning the order in which the cities are visited
+// and the cost of the tour. See SolveTSP for the defaults; opts are passed on to the colony
+func (tsp *TSPProblem) Solve(opts ...Option) (order []uint, cost float64, err error) {
+	if err := validateSquare(tsp.weights, len(tsp.weights), "weight"); err != nil {
+		return nil, 0, err
+	}
+
+	// With fewer than two cities there is nothing to optimize
+	if len(tsp.weights) < 2 {
+		order = make([]uint, len(tsp.weights))
+		return order, 0, nil
+	}
+
+	num_ants := tsp.Params.Ants
+
+	if num_ants == 0 {
+		num_ants = uint(len(tsp.weights))
+
+		if num_ants < minTSPAnts {
+			num_ants = minTSPAnts
+		}
+	}
+
+	num_iters := tsp.Params.Iterations
+
+	if num_iters == 0 {
+		num_iters = defaultTSPIters
+	}
+
+	// Report the cost in terms of the weights rather than the heuristics
+	defaults := []Option{WithAnts(int(num_ants)), WithTourCost(SumCost(tsp.weights))}
+
+	if tsp.Params.Seed != nil {
+		defaults = append(defaults, WithSeed(*tsp.Params.Seed))
+	}
+
+	colony, err := NewAntColony(tsp, append(defaults, opts...)...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	colony.RunSimulation(num_iters)
+
+	tour, cost := colony.BestSolution()
+
+	return TourOrder(tour), cost, nil
+}
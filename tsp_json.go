@@ -0,0 +1,75 @@
+package antcolony
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// A TSP instance as read by ParseProblemJSON, e.g.
+//
+//	{"nodes": 3, "weights": [[0, 1, 2], [1, 0, 3], [2, 3, 0]], "params": {"ants": 10, "iterations": 50, "seed": 1}}
+type problemSpec struct {
+	// Number of cities
+	Nodes int `json:"nodes"`
+	// A nodes x nodes matrix of non-negative weights, where weights[i][j] is the cost of going from city i to city j
+	Weights [][]float64 `json:"weights"`
+	// Optional solver parameters
+	Params TSPParams `json:"params"`
+}
+
+// The result of solving a TSP, as written by WriteResultJSON, e.g.
+//
+//	{"order": [0, 2, 1], "cost": 6}
+type TSPResult struct {
+	// The order in which the cities are visited; the tour returns to the first city at the end
+	Order []uint `json:"order"`
+	// The cost of the tour
+	Cost float64 `json:"cost"`
+}
+
+// Read a TSP from a JSON problem spec of the form {"nodes": n, "weights": [[...]], "params": {...}}.
+// The weight matrix must be n x n with finite non-negative entries; see TSPParams for the parameters.
+// This allows driving the solver from other languages, e.g. as a subprocess
+func ParseProblemJSON(r io.Reader) (*TSPProblem, error) {
+	var spec problemSpec
+
+	if err := json.NewDecoder(r).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse problem: %w", err)
+	}
+
+	if spec.Nodes <= 0 {
+		return nil, fmt.Errorf("%w: problem must have at least one node, got %d", ErrInvalidParams, spec.Nodes)
+	}
+
+	if len(spec.Weights) != spec.Nodes {
+		return nil, fmt.Errorf("%w: weight matrix has %d rows, expected %d", ErrRaggedMatrix, len(spec.Weights), spec.Nodes)
+	}
+
+	for i, row := range spec.Weights {
+		if len(row) != spec.Nodes {
+			return nil, fmt.Errorf("%w: weight matrix row %d has %d entries, expected %d", ErrRaggedMatrix, i, len(row), spec.Nodes)
+		}
+
+		for j, w := range row {
+			if w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+				return nil, fmt.Errorf("%w: weight (%d, %d) must be finite and non-negative, got %v", ErrInvalidParams, i, j, w)
+			}
+		}
+	}
+
+	if spec.Params.Iterations < 0 {
+		return nil, fmt.Errorf("%w: number of iterations must not be negative, got %d", ErrInvalidParams, spec.Params.Iterations)
+	}
+
+	tsp := NewTSPProblem(spec.Weights)
+	tsp.Params = spec.Params
+
+	return tsp, nil
+}
+
+// Write the result of solving a TSP as JSON of the form {"order": [...], "cost": c}
+func WriteResultJSON(w io.Writer, result TSPResult) error {
+	return json.NewEncoder(w).Encode(result)
+}
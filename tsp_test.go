@@ -0,0 +1,68 @@
+package antcolony
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSolveTSPRejectsRaggedWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights [][]float64
+	}{
+		{"short row", [][]float64{{0, 1, 2}, {1, 0}, {2, 3, 0}}},
+		{"long row", [][]float64{{0, 1, 2, 3}, {1, 0, 1}, {2, 1, 0}}},
+		{"missing row", [][]float64{{0, 1, 2}, {1, 0, 1}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := SolveTSP(test.weights); !errors.Is(err, ErrRaggedMatrix) {
+				t.Errorf("SolveTSP: got error %v, expected %v", err, ErrRaggedMatrix)
+			}
+
+			if _, err := NewAntColony(NewTSPProblem(test.weights)); !errors.Is(err, ErrRaggedMatrix) {
+				t.Errorf("NewAntColony: got error %v, expected %v", err, ErrRaggedMatrix)
+			}
+		})
+	}
+}
+
+func TestSolveTSPFindsTheOptimum(t *testing.T) {
+	weights := ringWeights(t, 8)
+	order, cost, err := SolveTSP(weights, WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cost != 8 {
+		t.Errorf("got cost %v, expected 8", cost)
+	}
+
+	if len(order) != 8 {
+		t.Errorf("got %d cities in the order, expected 8", len(order))
+	}
+}
+
+func TestTSPPheromonesWithZeroWeights(t *testing.T) {
+	weights := [][]float64{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+
+	for i, row := range NewTSPProblem(weights).InitPheromones(3) {
+		for j, tau := range row {
+			if tau != 1 {
+				t.Errorf("got initial pheromone %v on (%d, %d), expected 1", tau, i, j)
+			}
+		}
+	}
+
+	order, cost, err := SolveTSP(weights, WithSeed(1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cost != 0 || len(order) != 3 {
+		t.Errorf("got %v costing %v, expected a tour of the 3 cities costing 0", order, cost)
+	}
+}
@@ -0,0 +1,35 @@
+package antcolony
+
+import "fmt"
+
+// Check that a tour is a Hamiltonian cycle over the components that only uses edges of the construction graph
+func (colony *AntColony) validateGraphTour(tour []Edge) error {
+	if err := ValidateTour(tour, len(colony.constructionGraph.Nodes)); err != nil {
+		return err
+	}
+
+	for _, edge := range tour {
+		if !colony.constructionGraph.hasEdge(edge.A, edge.B) {
+			return fmt.Errorf("%w: edge (%d, %d) is not in the construction graph", ErrInfeasibleTour, edge.A, edge.B)
+		}
+	}
+
+	return nil
+}
+
+// Apply the initial tour set by WithInitialTour. This runs once all the options have been applied, since the cost
+// of the initial tour depends on WithTourCost, and again on Reset. The baseline set by WithBaselineBest needs no
+// setup: it's only compared against
+func (colony *AntColony) applyWarmStart() {
+	if colony.initialTour == nil {
+		return
+	}
+
+	cost := colony.tourCost(colony.initialTour)
+	colony.DepositTour(colony.initialTour, 1/cost)
+
+	if colony.improves(cost, colony.costToBeat()) {
+		colony.setBest(colony.initialTour, cost)
+		colony.optima = [][]Edge{colony.bestTour}
+	}
+}